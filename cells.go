@@ -0,0 +1,90 @@
+package gogol
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ReadCells reads a pattern in plaintext (.cells) format: '!' comment
+// lines, then one line per row with '.' for dead cells and 'O' for live ones.
+func ReadCells(r io.Reader) (*Pattern, error) {
+	p := &Pattern{}
+	var rows []string
+	w := 0
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<24)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), " \t\r")
+		if strings.HasPrefix(line, "!") {
+			text := strings.TrimSpace(line[1:])
+			if name, ok := strings.CutPrefix(text, "Name:"); ok {
+				p.Name = strings.TrimSpace(name)
+			} else if author, ok := strings.CutPrefix(text, "Author:"); ok {
+				p.Author = strings.TrimSpace(author)
+			} else {
+				p.Comments = append(p.Comments, text)
+			}
+			continue
+		}
+		if len(line) > w {
+			w = len(line)
+		}
+		rows = append(rows, line)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	p.Grid = NewGrid(w, len(rows))
+	for y, row := range rows {
+		for x := 0; x < len(row); x++ {
+			switch row[x] {
+			case '.':
+			case 'O', '*':
+				p.Cells[y][x] = true
+			default:
+				return nil, fmt.Errorf("gogol: cells: invalid character %q in row %d", row[x], y+1)
+			}
+		}
+	}
+	return p, nil
+}
+
+// WriteCells writes p in plaintext (.cells) format.
+func WriteCells(w io.Writer, p *Pattern) error {
+	bw := bufio.NewWriter(w)
+	if p.Name != "" {
+		fmt.Fprintf(bw, "!Name: %s\n", p.Name)
+	}
+	if p.Author != "" {
+		fmt.Fprintf(bw, "!Author: %s\n", p.Author)
+	}
+	for _, c := range p.Comments {
+		fmt.Fprintf(bw, "!%s\n", c)
+	}
+	writeRows(bw, p.Grid, 'O', '.')
+	return bw.Flush()
+}
+
+// writeRows writes one line per row of e, using alive and dead as the
+// characters of the cells.
+func writeRows(w io.Writer, e World, alive, dead byte) error {
+	r := e.Bounds()
+	line := make([]byte, 0, r.Dx()+1)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		line = line[:0]
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if e.Get(x, y) {
+				line = append(line, alive)
+			} else {
+				line = append(line, dead)
+			}
+		}
+		line = append(line, '\n')
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juansensio/gogol"
+)
+
+func benchCmd(args []string) error {
+	var o options
+	var sizes, engines, workers string
+	var runs int
+	var force bool
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	fs.StringVar(&sizes, "sizes", "100,200,500,1000", "comma separated list of world sizes")
+	fs.StringVar(&engines, "engines", "parallel,parallel2,parallel_matrix,bitpacked,parallel_bitpacked", "comma separated list of engines")
+	fs.StringVar(&workers, "workers", "2,4,8", "comma separated list of workers for parallel engines")
+	fs.IntVar(&o.its, "its", 100, "number of iterations per run")
+	fs.IntVar(&runs, "runs", 10, "number of runs to average")
+	o.soupFlags(fs)
+	fs.StringVar(&o.rule, "rule", "B3/S23", "rule of the automaton")
+	o.topologyFlag(fs)
+	o.hostsFlag(fs)
+	fs.StringVar(&o.output, "output", "benchmark.csv", "CSV file with the results")
+	fs.BoolVar(&force, "force", false, "overwrite the -output file if it exists")
+	fs.Parse(args)
+
+	if runs < 1 {
+		return fmt.Errorf("-runs must be at least 1, got %d", runs)
+	}
+
+	N, err := parseInts(sizes)
+	if err != nil {
+		return err
+	}
+	W, err := parseInts(workers)
+	if err != nil {
+		return err
+	}
+
+	// Create the CSV file, the results of an earlier run are only replaced
+	// with -force
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(o.output, flags, 0644)
+	if os.IsExist(err) {
+		return fmt.Errorf("output file %s already exists, use -force to overwrite it", o.output)
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Write CSV header
+	fmt.Fprintln(f, "size,algorithm,time_ms,iterations_per_second")
+
+	for _, n := range N {
+		fmt.Printf("Benchmarking size %d\n", n)
+		o.width, o.height = n, n
+		// every engine and run starts from the same soup
+		g, err := o.soup()
+		if err != nil {
+			return err
+		}
+		for _, name := range strings.Split(engines, ",") {
+			o.engine = name
+			o.workers = 1
+			e, err := o.newEngine(g)
+			if err != nil {
+				return err
+			}
+			_, concurrent := e.(gogol.Concurrent)
+			closeEngine(e)
+			if !concurrent {
+				if err := bench(f, &o, g, name, runs); err != nil {
+					return err
+				}
+				continue
+			}
+			for _, w := range W {
+				o.workers = w
+				if err := bench(f, &o, g, fmt.Sprintf("%s_%d", name, w), runs); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// bench times the engine selected by o starting from g and writes the mean
+// to f.
+func bench(f *os.File, o *options, g *gogol.Grid, label string, runs int) error {
+	fmt.Printf("%s implementation\n", label)
+	var totalTime time.Duration
+	skipped := -1.0
+	for i := 0; i < runs; i++ {
+		fmt.Printf("%d.", i+1)
+		e, err := o.newEngine(g)
+		if err != nil {
+			return err
+		}
+		start := time.Now()
+		e.StepN(o.its)
+		totalTime += time.Since(start)
+		if t, ok := e.(tiled); ok {
+			skipped = t.Stats().SkippedFraction()
+		}
+		err = engineErr(e)
+		closeEngine(e)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Println()
+	if skipped >= 0 {
+		fmt.Printf("%s implementation: %.1f%% of the tiles skipped\n", label, 100*skipped)
+	}
+	// at least a nanosecond, so a run too fast to measure does not give
+	// infinite iterations per second
+	meanTime := max(totalTime/time.Duration(runs), time.Nanosecond)
+	ms := float64(meanTime) / float64(time.Millisecond)
+	ips := float64(o.its) / meanTime.Seconds()
+	fmt.Printf("%s implementation: %.3f ms (%.1f iterations/s)\n", label, ms, ips)
+	_, err := fmt.Fprintf(f, "%d,%s,%.3f,%.1f\n", o.width, label, ms, ips)
+	return err
+}
+
+func parseInts(s string) ([]int, error) {
+	var out []int
+	for _, f := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
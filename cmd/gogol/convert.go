@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/juansensio/gogol"
+)
+
+func convertCmd(args []string) error {
+	var in, out, format string
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	fs.StringVar(&in, "input", "", "world to read, the format is detected from the content")
+	fs.StringVar(&out, "output", "", "world to write (.rle, .cells, .lif, .mc, .txt or .png)")
+	fs.StringVar(&format, "format", "", "output format (rle, cells, life105, life106, mc, text, png), by default from the extension")
+	fs.Parse(args)
+	if in == "" || out == "" {
+		return fmt.Errorf("convert needs -input and -output")
+	}
+
+	p, err := readPattern(in)
+	if err != nil {
+		return err
+	}
+	f := gogol.Format(format)
+	if f == "" {
+		f = gogol.FormatOf(out)
+	}
+	return writePattern(out, p, f)
+}
+
+func readPattern(path string) (*gogol.Pattern, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return gogol.ReadPattern(f)
+}
+
+// writePattern creates path and writes p to it in format f.
+func writePattern(path string, p *gogol.Pattern, format gogol.Format) error {
+	if format == "" {
+		return fmt.Errorf("unknown world format %q", path)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := gogol.WritePattern(f, p, format); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,278 @@
+// Command gogol runs, benchmarks, renders and converts Game of Life worlds.
+//
+// Usage:
+//
+//	gogol <command> [flags]
+//
+// The commands are:
+//
+//	run      simulate a world and print it in the terminal
+//	bench    compare the speed of the engines at different world sizes
+//	render   simulate a world and save every generation as an image
+//	convert  convert a world between file formats
+//	verify   check that every engine matches the reference engine of the rule
+//	worker   serve a band of the world for -engine distributed
+//
+// Run "gogol <command> -h" for the flags of each command.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juansensio/gogol"
+)
+
+var commands = map[string]func(args []string) error{
+	"run":     runCmd,
+	"bench":   benchCmd,
+	"render":  renderCmd,
+	"convert": convertCmd,
+	"verify":  verifyCmd,
+	"worker":  workerCmd,
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: gogol <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  run      simulate a world and print it in the terminal")
+	fmt.Fprintln(os.Stderr, "  bench    compare the speed of the engines at different world sizes")
+	fmt.Fprintln(os.Stderr, "  render   simulate a world and save every generation as an image")
+	fmt.Fprintln(os.Stderr, "  convert  convert a world between file formats")
+	fmt.Fprintln(os.Stderr, "  verify   check that every engine matches the reference engine of the rule")
+	fmt.Fprintln(os.Stderr, "  worker   serve a band of the world for -engine distributed")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "gogol: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd(os.Args[2:]); err != nil {
+		// errors of the gogol package already carry the prefix
+		fmt.Fprintln(os.Stderr, "gogol:", strings.TrimPrefix(err.Error(), "gogol: "))
+		os.Exit(1)
+	}
+}
+
+// options are the flags shared by the simulation commands.
+type options struct {
+	width    int
+	height   int
+	its      int
+	workers  int
+	seed     int64
+	density  float64
+	symmetry string
+	pattern  string
+	at       string
+	engine   string
+	rule     string
+	topology string
+	output   string
+	step     int
+	hosts    string
+	colormap string
+}
+
+func (o *options) world(fs *flag.FlagSet) {
+	fs.IntVar(&o.width, "width", 160, "world width in cells")
+	fs.IntVar(&o.height, "height", 40, "world height in cells")
+	fs.IntVar(&o.workers, "workers", gogol.DefaultWorkers, "number of workers for parallel engines")
+	o.soupFlags(fs)
+	fs.StringVar(&o.pattern, "pattern", "", "start from this pattern file instead of a random soup")
+	fs.StringVar(&o.at, "at", "", "x,y position of the pattern (default centered)")
+	fs.StringVar(&o.engine, "engine", "", "simulation engine (default naive, generations for rules with more than two states, ltl for Larger than Life, table for rule tables or lenia for continuous rules)")
+	fs.StringVar(&o.rule, "rule", "", "rule of the automaton, like B3/S23, HighLife, WireWorld, Orbium or a .rule file (default the pattern's rule or B3/S23)")
+	o.topologyFlag(fs)
+	o.hostsFlag(fs)
+}
+
+func (o *options) hostsFlag(fs *flag.FlagSet) {
+	fs.StringVar(&o.hosts, "hosts", "", "comma separated addresses of gogol worker processes for -engine distributed (default -workers local workers)")
+}
+
+func (o *options) colormapFlag(fs *flag.FlagSet) {
+	fs.StringVar(&o.colormap, "colormap", "", fmt.Sprintf("colour map of multi-state and continuous worlds: %s (default their own colours)", strings.Join(gogol.ColorMaps(), ", ")))
+}
+
+// colors returns w drawn with the colour map flag, if set.
+func (o *options) colors(w gogol.World) (gogol.World, error) {
+	if o.colormap == "" {
+		return w, nil
+	}
+	sw, ok := w.(gogol.StateWorld)
+	if !ok || sw.StateCount() <= 2 {
+		return nil, fmt.Errorf("-colormap needs a world with more than two states")
+	}
+	p, err := gogol.ColorMap(o.colormap, sw.StateCount())
+	if err != nil {
+		return nil, err
+	}
+	return gogol.Recolor(sw, p), nil
+}
+
+func (o *options) stepFlag(fs *flag.FlagSet) {
+	fs.IntVar(&o.step, "step", 0, "run 2^step generations per iteration, leaping with -engine hashlife")
+}
+
+// gens returns the generations run per iteration.
+func (o *options) gens() int {
+	return 1 << o.step
+}
+
+// view returns the part of e shown in the terminal, GIFs and videos: the
+// initial window for unbounded engines, whose bounds keep growing.
+func (o *options) view(e gogol.Engine) gogol.World {
+	if _, ok := e.(gogol.Unbounded); ok {
+		return gogol.View(e, image.Rect(0, 0, o.width, o.height))
+	}
+	return e
+}
+
+func (o *options) topologyFlag(fs *flag.FlagSet) {
+	fs.StringVar(&o.topology, "topology", "plane", "what lies beyond the edges: plane (dead cells), torus, cylinder, klein or cross")
+}
+
+func (o *options) soupFlags(fs *flag.FlagSet) {
+	fs.Int64Var(&o.seed, "seed", 1, "random seed of the initial soup")
+	fs.Float64Var(&o.density, "density", 0.3, "probability of a cell being alive in the initial soup")
+	fs.StringVar(&o.symmetry, "symmetry", "C1", "symmetry of the initial soup (C1, C2, C4, D2, D4, D8)")
+}
+
+// soup returns the initial grid selected by the flags.
+func (o *options) soup() (*gogol.Grid, error) {
+	s := gogol.Soup{Seed: o.seed, Density: o.density, Symmetry: gogol.Symmetry(o.symmetry)}
+	return s.Grid(o.width, o.height)
+}
+
+// newEngine builds the engine selected by the flags and loads g into it.
+func (o *options) newEngine(g *gogol.Grid) (gogol.Engine, error) {
+	rule, err := o.parseRule()
+	if err != nil {
+		return nil, err
+	}
+	topo, err := gogol.ParseTopology(o.topology)
+	if err != nil {
+		return nil, err
+	}
+	name := o.engine
+	if name == "" {
+		name = "naive"
+		if rule.Generations() {
+			name = "generations"
+		}
+		if rule.LtL() != nil {
+			name = "ltl"
+		}
+		if rule.Table() != nil {
+			name = "table"
+		}
+		if rule.Continuous() != nil {
+			name = "lenia"
+		}
+	}
+	var hosts []string
+	if o.hosts != "" {
+		hosts = strings.Split(o.hosts, ",")
+	}
+	e, err := gogol.New(name, gogol.Config{Width: o.width, Height: o.height, Workers: o.workers, Rule: rule, Topology: topo, Hosts: hosts})
+	if err != nil {
+		return nil, err
+	}
+	gogol.Load(e, g)
+	return e, nil
+}
+
+// parseRule parses the rule flag, reading .rule and .table files as rule
+// tables.
+func (o *options) parseRule() (*gogol.Rule, error) {
+	s := o.rule
+	if s == "" {
+		s = "B3/S23"
+	}
+	if ext := filepath.Ext(s); ext == ".rule" || ext == ".table" {
+		f, err := os.Open(s)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		t, err := gogol.ReadRuleTable(f)
+		if err != nil {
+			return nil, err
+		}
+		r := t.Rule()
+		return &r, nil
+	}
+	r, err := gogol.ParseRule(s)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// failed is implemented by engines that can fail, like the distributed one.
+type failed interface {
+	Err() error
+}
+
+// engineErr returns the error of e, if it can fail.
+func engineErr(e gogol.Engine) error {
+	if f, ok := e.(failed); ok {
+		return f.Err()
+	}
+	return nil
+}
+
+// closeEngine stops the workers of e, if it has any.
+func closeEngine(e gogol.Engine) {
+	if c, ok := e.(io.Closer); ok {
+		c.Close()
+	}
+}
+
+// start builds the engine selected by the flags with the initial pattern
+// or soup.
+func (o *options) start() (gogol.Engine, error) {
+	if o.pattern == "" {
+		g, err := o.soup()
+		if err != nil {
+			return nil, err
+		}
+		return o.newEngine(g)
+	}
+	p, err := readPattern(o.pattern)
+	if err != nil {
+		return nil, err
+	}
+	if o.rule == "" {
+		o.rule = p.Rule
+	}
+	size := p.Size()
+	x, y := (o.width-size.X)/2, (o.height-size.Y)/2
+	if o.at != "" {
+		p, err := parseInts(o.at)
+		if err != nil || len(p) != 2 {
+			return nil, fmt.Errorf("invalid position %q", o.at)
+		}
+		x, y = p[0], p[1]
+	}
+	e, err := o.newEngine(gogol.NewGrid(o.width, o.height))
+	if err != nil {
+		return nil, err
+	}
+	gogol.LoadPattern(e, p, x, y)
+	return e, nil
+}
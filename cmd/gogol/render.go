@@ -0,0 +1,162 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juansensio/gogol"
+)
+
+func renderCmd(args []string) error {
+	var o options
+	var delay time.Duration
+	var palette string
+	var opts gogol.GIFOptions
+	var fps, quality int
+	var force bool
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	o.world(fs)
+	fs.IntVar(&o.its, "its", 100, "number of iterations")
+	fs.StringVar(&o.output, "output", "out", "folder for the PNG frames, or a .gif, .y4m or .avi file")
+	fs.BoolVar(&force, "force", false, "delete the contents of the -output folder if it is not empty")
+	fs.DurationVar(&delay, "delay", 100*time.Millisecond, "delay between GIF frames")
+	fs.IntVar(&opts.Scale, "scale", 1, "pixels per cell in GIF and video frames")
+	fs.StringVar(&palette, "palette", "000000,ffffff", "dead and live colours of GIF frames")
+	fs.IntVar(&opts.Skip, "skip", 0, "generations skipped between GIF frames")
+	fs.IntVar(&opts.LoopCount, "loop", 0, "GIF loop count, 0 loops forever and -1 plays once")
+	fs.IntVar(&fps, "fps", 10, "frames per second of videos")
+	fs.IntVar(&quality, "quality", 90, "JPEG quality of .avi videos")
+	o.stepFlag(fs)
+	o.colormapFlag(fs)
+	fs.Parse(args)
+
+	// anything with an extension is a file, never the folder of the frames
+	ext := strings.ToLower(filepath.Ext(o.output))
+	switch ext {
+	case "", ".gif", ".y4m", ".avi":
+	default:
+		return fmt.Errorf("unsupported output format %s, use a folder or a .gif, .y4m or .avi file", ext)
+	}
+
+	e, err := o.start()
+	if err != nil {
+		return err
+	}
+	defer closeEngine(e)
+
+	// GIF and video frames keep the size of the first one
+	view, err := o.colors(o.view(e))
+	if err != nil {
+		return err
+	}
+	frame, err := o.colors(e)
+	if err != nil {
+		return err
+	}
+
+	switch ext {
+	case ".gif":
+		opts.Delay = int(delay / (10 * time.Millisecond))
+		if opts.Palette, err = gogol.ParsePalette(palette); err != nil {
+			return err
+		}
+		return renderGIF(e, view, o.its, o.gens(), o.output, opts)
+	case ".y4m", ".avi":
+		return renderVideo(e, view, o.its, o.gens(), o.output, opts.Scale, fps, quality)
+	}
+
+	if err := frameFolder(o.output, force); err != nil {
+		return err
+	}
+
+	for i := 0; i < o.its; i++ {
+		e.StepN(o.gens())
+		if err := engineErr(e); err != nil {
+			return err
+		}
+		if err := gogol.SavePNG(filepath.Join(o.output, fmt.Sprintf("%d.png", i)), frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// frameFolder creates the folder of the PNG frames. A folder that already
+// holds files is only emptied if force is set, so a mistyped -output does not
+// wipe anything else.
+func frameFolder(path string, force bool) error {
+	entries, err := os.ReadDir(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(entries) > 0 {
+		if !force {
+			return fmt.Errorf("output folder %s is not empty, use -force to delete its contents", path)
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	}
+	return os.MkdirAll(path, 0755)
+}
+
+func renderGIF(e gogol.Engine, view gogol.World, its, gens int, path string, opts gogol.GIFOptions) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	g := gogol.NewGIFWriter(f, opts)
+	for i := 0; i < its; i++ {
+		e.StepN(gens)
+		if err := engineErr(e); err != nil {
+			f.Close()
+			return err
+		}
+		g.Add(view)
+	}
+	if err := g.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// videoWriter is implemented by gogol.Y4MWriter and gogol.AVIWriter.
+type videoWriter interface {
+	WriteFrame(img image.Image) error
+	Close() error
+}
+
+func renderVideo(e gogol.Engine, view gogol.World, its, gens int, path string, scale, fps, quality int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	var v videoWriter
+	if strings.ToLower(filepath.Ext(path)) == ".avi" {
+		v = gogol.NewAVIWriter(f, fps, quality)
+	} else {
+		v = gogol.NewY4MWriter(f, fps)
+	}
+	for i := 0; i < its; i++ {
+		e.StepN(gens)
+		if err := engineErr(e); err != nil {
+			f.Close()
+			return err
+		}
+		if err := v.WriteFrame(gogol.Frame(view, scale)); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := v.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juansensio/gogol"
+)
+
+func runCmd(args []string) error {
+	var o options
+	var delay time.Duration
+	var cells, force bool
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	o.world(fs)
+	fs.IntVar(&o.its, "its", 100, "number of iterations")
+	fs.DurationVar(&delay, "delay", 100*time.Millisecond, "wait between iterations")
+	fs.BoolVar(&cells, "cells", false, "print generations in plaintext (.cells) format instead of clearing the terminal")
+	fs.StringVar(&o.output, "output", "", "also save every generation as a PNG in this folder")
+	fs.BoolVar(&force, "force", false, "delete the contents of the -output folder if it is not empty")
+	o.stepFlag(fs)
+	o.colormapFlag(fs)
+	fs.Parse(args)
+
+	e, err := o.start()
+	if err != nil {
+		return err
+	}
+	defer closeEngine(e)
+	if o.output != "" {
+		if err := frameFolder(o.output, force); err != nil {
+			return err
+		}
+	}
+
+	view, err := o.colors(o.view(e))
+	if err != nil {
+		return err
+	}
+	frame, err := o.colors(e)
+	if err != nil {
+		return err
+	}
+
+	// iterate
+	for i := 0; i < o.its; i++ {
+		e.StepN(o.gens())
+		if err := engineErr(e); err != nil {
+			return err
+		}
+		time.Sleep(delay)
+		if cells {
+			gogol.PrintCells(os.Stdout, i, view)
+		} else {
+			gogol.PrintTerminal(os.Stdout, i, view)
+		}
+		if _, ok := e.(gogol.Unbounded); ok {
+			fmt.Printf("Bounds: %v Population: %d\n", e.Bounds(), e.Population())
+		}
+		if c, ok := e.(continuous); ok {
+			fmt.Printf("Mass: %.1f Population: %d\n", c.Mass(), e.Population())
+		}
+		if t, ok := e.(tiled); ok {
+			s := t.Stats()
+			fmt.Printf("Tiles: %d/%d active, %.1f%% skipped\n", s.Active, s.Tiles, 100*s.SkippedFraction())
+		}
+		if o.output != "" {
+			if err := gogol.SavePNG(filepath.Join(o.output, fmt.Sprintf("%d.png", i)), frame); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// tiled is implemented by engines that skip the tiles that did not change.
+type tiled interface {
+	Stats() gogol.TileStats
+}
+
+// continuous is implemented by engines whose cells hold values between 0
+// and 1.
+type continuous interface {
+	Mass() float64
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/juansensio/gogol"
+)
+
+func verifyCmd(args []string) error {
+	var o options
+	var sizes, engines, workers string
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	fs.StringVar(&sizes, "sizes", "1,2,3,5,8,13,21,34,55", "comma separated list of world sizes")
+	fs.StringVar(&engines, "engines", "", "comma separated list of engines (default all that support the rule)")
+	fs.StringVar(&workers, "workers", "1,2,3,4,7", "comma separated list of workers for parallel engines")
+	fs.IntVar(&o.its, "its", 50, "number of generations to compare")
+	fs.StringVar(&o.rule, "rule", "B3/S23", "rule of the automaton")
+	o.topologyFlag(fs)
+	o.hostsFlag(fs)
+	o.soupFlags(fs)
+	fs.Parse(args)
+
+	N, err := parseInts(sizes)
+	if err != nil {
+		return err
+	}
+	W, err := parseInts(workers)
+	if err != nil {
+		return err
+	}
+	rule, err := o.parseRule()
+	if err != nil {
+		return err
+	}
+	topo, err := gogol.ParseTopology(o.topology)
+	if err != nil {
+		return err
+	}
+	var hosts []string
+	if o.hosts != "" {
+		hosts = strings.Split(o.hosts, ",")
+	}
+	var names []string
+	if engines != "" {
+		names = strings.Split(engines, ",")
+	} else {
+		// every engine that can run the rule on the topology
+		for _, name := range gogol.Engines() {
+			if e, err := gogol.New(name, gogol.Config{Width: 1, Height: 1, Rule: rule, Topology: topo}); err == nil {
+				closeEngine(e)
+				names = append(names, name)
+			}
+		}
+	}
+
+	for _, n := range N {
+		// non square worlds catch engines mixing up rows and columns
+		for _, size := range [][2]int{{n, n}, {n, n + 1}, {n + 2, n}} {
+			o.width, o.height = size[0], size[1]
+			g, err := o.soup()
+			if err != nil {
+				return err
+			}
+			for _, w := range W {
+				c := gogol.Config{Width: o.width, Height: o.height, Workers: w, Rule: rule, Topology: topo, Hosts: hosts}
+				d, err := gogol.Verify(names, c, g, o.its)
+				if err != nil {
+					return err
+				}
+				if d != nil {
+					return fmt.Errorf("size %dx%d, %d workers: %w", o.width, o.height, w, d)
+				}
+			}
+			fmt.Printf("%dx%d ok\n", o.width, o.height)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+
+	"github.com/juansensio/gogol"
+)
+
+func workerCmd(args []string) error {
+	var addr string
+	fs := flag.NewFlagSet("worker", flag.ExitOnError)
+	fs.StringVar(&addr, "listen", "127.0.0.1:7070", "address to listen on for the coordinator and the other workers")
+	fs.Parse(args)
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("worker listening on %s\n", l.Addr())
+	return gogol.ServeWorker(l)
+}
@@ -0,0 +1,54 @@
+package gogol
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+)
+
+// colorMaps are the colours of the colour maps at equally spaced points,
+// from the lowest state to the highest.
+var colorMaps = map[string][]string{
+	"gray":    {"000000", "ffffff"},
+	"viridis": {"440154", "3b528b", "21918c", "5ec962", "fde725"},
+	"inferno": {"000004", "420a68", "932667", "dd513a", "fca50a", "fcffa4"},
+	"jet":     {"00007f", "0000ff", "007fff", "00ffff", "7fff7f", "ffff00", "ff7f00", "ff0000", "7f0000"},
+}
+
+// ColorMaps returns the names of the colour maps in sorted order.
+func ColorMaps() []string {
+	var names []string
+	for name := range colorMaps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ColorMap returns n colours going through the colour map name, for the
+// states of continuous or multi-state worlds.
+func ColorMap(name string, n int) (color.Palette, error) {
+	m, ok := colorMaps[name]
+	if !ok {
+		return nil, fmt.Errorf("gogol: unknown colour map %q", name)
+	}
+	stops, err := ParsePalette(strings.Join(m, ","))
+	if err != nil {
+		return nil, err
+	}
+	p := make(color.Palette, n)
+	for s := range p {
+		// Interpolate between the two stops around the state
+		t := 0.0
+		if n > 1 {
+			t = float64(s) / float64(n-1) * float64(len(stops)-1)
+		}
+		i := min(int(t), len(stops)-2)
+		a, b := stops[i].(color.RGBA), stops[i+1].(color.RGBA)
+		f := t - float64(i)
+		mix := func(x, y uint8) uint8 { return uint8(float64(x) + f*(float64(y)-float64(x)) + 0.5) }
+		p[s] = color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 255}
+	}
+	return p, nil
+}
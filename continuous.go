@@ -0,0 +1,287 @@
+package gogol
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/juansensio/gogol/fft"
+)
+
+// MaxKernelRadius is the largest kernel radius of continuous rules.
+const MaxKernelRadius = 100
+
+// Continuous holds the parameters of a continuous automaton, Lenia or
+// SmoothLife, whose cells hold values between 0 and 1. The neighbourhood of
+// a cell is a kernel of weights summing to 1 around it, and every time step
+// moves the cells towards the values given by the potentials, the weighted
+// sums of their neighbourhoods.
+type Continuous struct {
+	Radius int     // R, radius of the kernel in cells
+	DT     float64 // length of a time step, 1/T
+
+	// Lenia: the kernel is a set of concentric rings and a cell grows by DT
+	// times the growth of its potential.
+	Peaks  []float64 // b, heights of the rings from the centre out
+	Core   Core      // kn, profile of each ring
+	Growth Growth    // gn, growth function
+	Mu     float64   // m, potential with the fastest growth
+	Sigma  float64   // s, width of the growth function
+
+	// SmoothLife, nil for Lenia: the potentials are the filling of the
+	// inner disk and of the ring around it, out to Radius.
+	Smooth *SmoothLife
+}
+
+// SmoothLife holds the parameters of Rafler's SmoothLife. A cell moves by DT
+// of the way towards the value of the transition function, so with DT 1 it
+// takes that value right away as in the original discrete time version,
+// whose gliders need sparse starts; from a soup it mostly dies out.
+type SmoothLife struct {
+	Inner  float64    // ri, radius of the inner disk
+	Birth  [2]float64 // b1, b2, filling of the ring for a dead cell to be born
+	Death  [2]float64 // d1, d2, filling of the ring for a live cell to survive
+	AlphaN float64    // an, smoothness of the steps of the ring filling
+	AlphaM float64    // am, smoothness of the step between dead and alive
+}
+
+// Core is the profile of the rings of a Lenia kernel, numbered as Lenia's kn.
+type Core int
+
+const (
+	CorePolynomial  Core = 1 // (4r(1-r))^4
+	CoreExponential Core = 2 // exp(4 - 1/(r(1-r)))
+)
+
+// Growth is the growth function of Lenia, numbered as Lenia's gn.
+type Growth int
+
+const (
+	GrowthPolynomial Growth = 1 // 2(1-(u-m)²/9s²)^4 - 1, -1 beyond 3s
+	GrowthGaussian   Growth = 2 // 2exp(-(u-m)²/2s²) - 1
+)
+
+// Continuous returns the parameters of a continuous rule, or nil if the
+// cells of the rule are only alive or dead.
+func (r *Rule) Continuous() *Continuous {
+	return r.continuous
+}
+
+// parseContinuous parses a rule given as key=value fields separated by
+// semicolons: Lenia's "R=13;T=10;b=1;m=0.15;s=0.015;kn=1;gn=1", with the
+// peaks of the rings separated by commas and maybe as fractions, or
+// SmoothLife's "ra=21;ri=7;b1=0.278;b2=0.365;d1=0.267;d2=0.445;an=0.028;am=0.147;dt=0.1".
+// Missing fields take the values of these examples, but the radius and, for
+// Lenia, m and s are required.
+func parseContinuous(s string) (Rule, error) {
+	c := &Continuous{DT: 0.1, Peaks: []float64{1}, Core: CorePolynomial, Growth: GrowthPolynomial}
+	sl := &SmoothLife{Birth: [2]float64{0.278, 0.365}, Death: [2]float64{0.267, 0.445}, AlphaN: 0.028, AlphaM: 0.147}
+	r := Rule{States: 2, continuous: c}
+	seen := map[string]bool{}
+	for _, f := range strings.Split(s, ";") {
+		k, v, ok := strings.Cut(strings.TrimSpace(f), "=")
+		k = strings.ToLower(strings.TrimSpace(k))
+		if !ok || k == "" {
+			return r, fmt.Errorf("gogol: invalid rule %q", s)
+		}
+		seen[k] = true
+		var err error
+		switch k {
+		case "r", "ra":
+			c.Radius, err = strconv.Atoi(v)
+			if err == nil && (c.Radius < 1 || c.Radius > MaxKernelRadius) {
+				err = fmt.Errorf("radius must be between 1 and %d", MaxKernelRadius)
+			}
+		case "t":
+			var t float64
+			if t, err = parseFloat(v); err == nil {
+				c.DT = 1 / t
+			}
+		case "dt":
+			c.DT, err = parseFloat(v)
+		case "b":
+			c.Peaks = nil
+			for _, p := range strings.Split(v, ",") {
+				var b float64
+				if b, err = parseFloat(p); err != nil {
+					break
+				}
+				c.Peaks = append(c.Peaks, b)
+			}
+		case "m":
+			c.Mu, err = parseFloat(v)
+		case "s":
+			c.Sigma, err = parseFloat(v)
+		case "kn":
+			var n int
+			n, err = strconv.Atoi(v)
+			c.Core = Core(n)
+			if err == nil && c.Core != CorePolynomial && c.Core != CoreExponential {
+				err = fmt.Errorf("unknown kernel core %d", n)
+			}
+		case "gn":
+			var n int
+			n, err = strconv.Atoi(v)
+			c.Growth = Growth(n)
+			if err == nil && c.Growth != GrowthPolynomial && c.Growth != GrowthGaussian {
+				err = fmt.Errorf("unknown growth function %d", n)
+			}
+		case "ri":
+			sl.Inner, err = parseFloat(v)
+		case "b1":
+			sl.Birth[0], err = parseFloat(v)
+		case "b2":
+			sl.Birth[1], err = parseFloat(v)
+		case "d1":
+			sl.Death[0], err = parseFloat(v)
+		case "d2":
+			sl.Death[1], err = parseFloat(v)
+		case "an":
+			sl.AlphaN, err = parseFloat(v)
+		case "am":
+			sl.AlphaM, err = parseFloat(v)
+		default:
+			err = fmt.Errorf("unknown field %q", f)
+		}
+		if err != nil {
+			return r, fmt.Errorf("gogol: invalid rule %q: %v", s, err)
+		}
+	}
+	required := []string{"r", "m", "s"}
+	if seen["ra"] {
+		c.Smooth = sl
+		if !seen["ri"] {
+			sl.Inner = float64(c.Radius) / 3
+		}
+		required = []string{"ra"}
+	}
+	for _, k := range required {
+		if !seen[k] {
+			return r, fmt.Errorf("gogol: invalid rule %q: missing %s", s, k)
+		}
+	}
+	if c.Smooth == nil && c.Sigma <= 0 {
+		return r, fmt.Errorf("gogol: invalid rule %q: s must be above 0", s)
+	}
+	if c.DT <= 0 || c.DT > 1 {
+		return r, fmt.Errorf("gogol: invalid rule %q: the time step must be between 0 and 1", s)
+	}
+	return r, nil
+}
+
+// parseFloat parses a number, maybe written as a fraction like "2/3".
+func parseFloat(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if n, d, ok := strings.Cut(s, "/"); ok {
+		a, err1 := strconv.ParseFloat(n, 64)
+		b, err2 := strconv.ParseFloat(d, 64)
+		if err1 != nil || err2 != nil || b == 0 {
+			return 0, fmt.Errorf("invalid number %q", s)
+		}
+		return a / b, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// format returns the rule in the notation read by parseContinuous.
+func (c *Continuous) format() string {
+	g := func(f float64) string { return strconv.FormatFloat(f, 'g', -1, 64) }
+	if s := c.Smooth; s != nil {
+		return fmt.Sprintf("ra=%d;ri=%s;b1=%s;b2=%s;d1=%s;d2=%s;an=%s;am=%s;dt=%s", c.Radius, g(s.Inner),
+			g(s.Birth[0]), g(s.Birth[1]), g(s.Death[0]), g(s.Death[1]), g(s.AlphaN), g(s.AlphaM), g(c.DT))
+	}
+	var peaks []string
+	for _, b := range c.Peaks {
+		peaks = append(peaks, g(b))
+	}
+	step := "dt=" + g(c.DT)
+	if t := 1 / c.DT; t == math.Round(t) {
+		step = "T=" + g(t)
+	}
+	return fmt.Sprintf("R=%d;%s;b=%s;m=%s;s=%s;kn=%d;gn=%d", c.Radius, step, strings.Join(peaks, ","), g(c.Mu), g(c.Sigma), c.Core, c.Growth)
+}
+
+// kernels returns the kernels of the potentials, each one summing to 1: the
+// rings of Lenia, or the inner disk and the ring of SmoothLife. The edges of
+// the disk and the ring are antialiased over one cell.
+func (c *Continuous) kernels() [][]fft.Tap {
+	R := c.Radius
+	var disk, ring []fft.Tap
+	for dy := -R; dy <= R; dy++ {
+		for dx := -R; dx <= R; dx++ {
+			d := math.Hypot(float64(dx), float64(dy))
+			if c.Smooth != nil {
+				inner := clamp(c.Smooth.Inner+0.5-d, 0, 1)
+				outer := clamp(float64(R)+0.5-d, 0, 1)
+				disk = append(disk, fft.Tap{X: dx, Y: dy, Weight: inner})
+				ring = append(ring, fft.Tap{X: dx, Y: dy, Weight: outer - inner})
+				continue
+			}
+			if d >= float64(R) {
+				continue
+			}
+			// Ring of the cell and its distance across it
+			br := float64(len(c.Peaks)) * d / float64(R)
+			ring = append(ring, fft.Tap{X: dx, Y: dy, Weight: c.Peaks[int(br)] * c.Core.at(br-math.Floor(br))})
+		}
+	}
+	if c.Smooth != nil {
+		return [][]fft.Tap{normalize(disk), normalize(ring)}
+	}
+	return [][]fft.Tap{normalize(ring)}
+}
+
+func normalize(k []fft.Tap) []fft.Tap {
+	sum := 0.0
+	for _, t := range k {
+		sum += t.Weight
+	}
+	for i := range k {
+		k[i].Weight /= sum
+	}
+	return k
+}
+
+// at returns the height of the profile at r across the ring, 0 <= r < 1.
+func (k Core) at(r float64) float64 {
+	if k == CoreExponential {
+		if r <= 0 {
+			return 0
+		}
+		return math.Exp(4 - 1/(r*(1-r)))
+	}
+	return math.Pow(4*r*(1-r), 4)
+}
+
+// at returns the growth of potential u, between -1 and 1.
+func (g Growth) at(u, mu, sigma float64) float64 {
+	d := (u - mu) / sigma
+	if g == GrowthGaussian {
+		return 2*math.Exp(-d*d/2) - 1
+	}
+	return 2*math.Pow(max(0, 1-d*d/9), 4) - 1
+}
+
+// next returns the value of a cell after a time step, given its potentials.
+func (c *Continuous) next(a float64, u []float64) float64 {
+	if s := c.Smooth; s != nil {
+		m, n := u[0], u[1]
+		// Alive as much as the inner disk is full
+		alive := sigmoid(m, 0.5, s.AlphaM)
+		lo := s.Birth[0]*(1-alive) + s.Death[0]*alive
+		hi := s.Birth[1]*(1-alive) + s.Death[1]*alive
+		t := sigmoid(n, lo, s.AlphaN) * (1 - sigmoid(n, hi, s.AlphaN))
+		return clamp(a+c.DT*(t-a), 0, 1)
+	}
+	return clamp(a+c.DT*c.Growth.at(u[0], c.Mu, c.Sigma), 0, 1)
+}
+
+// sigmoid is a smooth step from 0 to 1 at a, of width alpha.
+func sigmoid(x, a, alpha float64) float64 {
+	return 1 / (1 + math.Exp(-4*(x-a)/alpha))
+}
+
+func clamp(x, lo, hi float64) float64 {
+	return min(max(x, lo), hi)
+}
@@ -0,0 +1,450 @@
+package gogol
+
+import (
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// The distributed engine splits the world into tiles, each one held by a
+// worker process. The coordinator talks to every worker over its own
+// connection with gob encoded requests, and the workers owning neighbouring
+// tiles connect to each other to exchange the cells along the edges of their
+// tiles every generation as one byte per cell, the rest of the world never
+// leaving the worker that owns it.
+
+// Kinds of connection, sent as the first byte.
+const (
+	connCoordinator byte = 'C'
+	connPeer        byte = 'P' // followed by the session as 8 bytes and the index of the worker as 4
+)
+
+// linkTimeout bounds every wait of the distributed engine for another
+// process: connecting to it, the links between the workers and each exchange
+// of halo cells, so a missing or stuck worker is reported instead of
+// blocking the others forever.
+var linkTimeout = 30 * time.Second
+
+// request is sent by the coordinator to a worker.
+type request struct {
+	Op    string // init, set, step, gather or population
+	Init  *initRequest
+	Cells []cellState // set
+	Steps int         // step
+}
+
+type initRequest struct {
+	Session  uint64 // identifies the links between the workers of a coordinator
+	Index    int    // of the worker
+	Width    int
+	Height   int
+	Rule     string
+	Topology Topology
+	Tiles    []image.Rectangle // tile of every worker
+	Peers    []string          // addresses of every worker
+}
+
+type cellState struct {
+	X, Y  int // in the whole world
+	Alive bool
+}
+
+// response is sent by a worker after each request.
+type response struct {
+	Err        string
+	Population int
+	Rows       [][]bool // gather
+}
+
+// tiling splits a w x h world between n workers in a grid of tiles whose
+// sides are as even as possible, so the workers exchange as few cells as
+// possible. It returns nil if n workers cannot all get a cell.
+func tiling(w, h, n int) []image.Rectangle {
+	best, cols := 0.0, 0
+	for c := 1; c <= n; c++ {
+		if n%c != 0 || c > w || n/c > h {
+			continue
+		}
+		// half the perimeter of a tile
+		p := float64(w)/float64(c) + float64(h)/float64(n/c)
+		if cols == 0 || p < best {
+			best, cols = p, c
+		}
+	}
+	if cols == 0 {
+		return nil
+	}
+	var tiles []image.Rectangle
+	for j := 0; j < n/cols; j++ {
+		y0, y1 := band(j, n/cols, h)
+		for i := 0; i < cols; i++ {
+			x0, x1 := band(i, cols, w)
+			tiles = append(tiles, image.Rect(x0, y0, x1, y1))
+		}
+	}
+	return tiles
+}
+
+// owner returns the tile holding the cell at (x, y).
+func owner(tiles []image.Rectangle, x, y int) int {
+	p := image.Pt(x, y)
+	for i, t := range tiles {
+		if p.In(t) {
+			return i
+		}
+	}
+	return -1
+}
+
+// halo calls f for every ghost cell of tile i, row by row, with its position
+// in the padded tile, the tile owning the cell it stands for and the
+// position of that cell in the padded owner tile. Ghost cells beyond an open
+// edge are left out.
+func halo(init *initRequest, i int, f func(ghost image.Point, o int, src image.Point)) {
+	t := init.Tiles[i]
+	for py := 0; py <= t.Dy()+1; py++ {
+		for px := 0; px <= t.Dx()+1; px++ {
+			if py > 0 && py <= t.Dy() && px > 0 && px <= t.Dx() {
+				px = t.Dx() // skip the interior of the row
+				continue
+			}
+			x, y, ok := init.Topology.wrap(t.Min.X+px-1, t.Min.Y+py-1, init.Width, init.Height)
+			if !ok {
+				continue
+			}
+			o := owner(init.Tiles, x, y)
+			f(image.Pt(px, py), o, image.Pt(x-init.Tiles[o].Min.X+1, y-init.Tiles[o].Min.Y+1))
+		}
+	}
+}
+
+// ServeWorker runs a worker of the distributed engine, accepting
+// coordinators and the connections of the other workers on l until it is
+// closed.
+func ServeWorker(l net.Listener) error {
+	peers := &peerLinks{links: make(map[uint64]chan peerConn)}
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			var hello [13]byte
+			conn.SetReadDeadline(time.Now().Add(linkTimeout))
+			if _, err := io.ReadFull(conn, hello[:1]); err != nil {
+				conn.Close()
+				return
+			}
+			switch hello[0] {
+			case connCoordinator:
+				conn.SetReadDeadline(time.Time{})
+				serveCoordinator(conn, peers)
+			case connPeer:
+				if _, err := io.ReadFull(conn, hello[1:]); err != nil {
+					conn.Close()
+					return
+				}
+				conn.SetReadDeadline(time.Time{})
+				session := binary.BigEndian.Uint64(hello[1:9])
+				p := peerConn{conn, int(binary.BigEndian.Uint32(hello[9:]))}
+				// Nobody takes the link if the session gave up waiting
+				select {
+				case peers.get(session) <- p:
+				case <-time.After(linkTimeout):
+					peers.remove(session)
+					conn.Close()
+				}
+			default:
+				conn.Close()
+			}
+		}()
+	}
+}
+
+// peerConn is a connection from another worker.
+type peerConn struct {
+	net.Conn
+	from int
+}
+
+// peerLinks holds the connections from other workers until the session
+// they belong to takes them.
+type peerLinks struct {
+	mu    sync.Mutex
+	links map[uint64]chan peerConn
+}
+
+func (p *peerLinks) get(session uint64) chan peerConn {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c, ok := p.links[session]
+	if !ok {
+		c = make(chan peerConn)
+		p.links[session] = c
+	}
+	return c
+}
+
+func (p *peerLinks) remove(session uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.links, session)
+}
+
+// session is the tile of a worker, padded with a ring of ghost cells, and
+// its links to the workers owning the cells of the ring.
+type session struct {
+	init   *initRequest
+	tile   image.Rectangle
+	cells  [][]bool // rows tile.Min.Y-1 to tile.Max.Y, columns tile.Min.X-1 to tile.Max.X
+	next   [][]bool
+	rule   Rule
+	copies []ghostCopy // ghost cells standing for cells of the tile itself
+	links  []*peerLink
+	peers  *peerLinks
+}
+
+// ghostCopy is a ghost cell standing for a cell of the same tile, across a
+// wrapped edge.
+type ghostCopy struct {
+	dst, src image.Point
+}
+
+// peerLink is the connection to a worker owning a neighbouring tile, with
+// the cells sent to it and the ghost cells it fills, both in the order of
+// the ring of the tile they are ghosts for.
+type peerLink struct {
+	conn net.Conn
+	peer int
+	send []image.Point
+	recv []image.Point
+	wbuf []byte
+	rbuf []byte
+}
+
+func serveCoordinator(conn net.Conn, peers *peerLinks) {
+	defer conn.Close()
+	dec := gob.NewDecoder(conn)
+	enc := gob.NewEncoder(conn)
+	s := &session{peers: peers}
+	defer s.close()
+	for {
+		var req request
+		if err := dec.Decode(&req); err != nil {
+			return
+		}
+		resp, err := s.handle(&req)
+		if err != nil {
+			resp.Err = err.Error()
+		}
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+func (s *session) handle(req *request) (response, error) {
+	if req.Op != "init" && s.cells == nil {
+		return response{}, fmt.Errorf("gogol: worker not initialized")
+	}
+	switch req.Op {
+	case "init":
+		return response{}, s.start(req.Init)
+	case "set":
+		for _, c := range req.Cells {
+			if image.Pt(c.X, c.Y).In(s.tile) {
+				s.cells[c.Y-s.tile.Min.Y+1][c.X-s.tile.Min.X+1] = c.Alive
+			}
+		}
+		return response{}, nil
+	case "step":
+		for i := 0; i < req.Steps; i++ {
+			if err := s.exchange(); err != nil {
+				return response{}, err
+			}
+			s.step()
+		}
+		return response{Population: s.population()}, nil
+	case "population":
+		return response{Population: s.population()}, nil
+	case "gather":
+		var rows [][]bool
+		for _, row := range s.cells[1 : len(s.cells)-1] {
+			rows = append(rows, row[1:len(row)-1])
+		}
+		return response{Rows: rows}, nil
+	}
+	return response{}, fmt.Errorf("gogol: unknown request %q", req.Op)
+}
+
+// start builds the tile and connects to the workers of the neighbouring
+// tiles. The worker with the lower index of each pair dials the other one.
+func (s *session) start(init *initRequest) error {
+	s.close()
+	s.cells = nil
+	rule, err := ParseRule(init.Rule)
+	if err != nil {
+		return err
+	}
+	if init.Index < 0 || init.Index >= len(init.Tiles) || len(init.Peers) != len(init.Tiles) {
+		return fmt.Errorf("gogol: worker %d of %d tiles and %d peers", init.Index, len(init.Tiles), len(init.Peers))
+	}
+	s.init, s.rule, s.tile = init, rule, init.Tiles[init.Index]
+	links := make(map[int]*peerLink)
+	link := func(peer int) *peerLink {
+		l, ok := links[peer]
+		if !ok {
+			l = &peerLink{peer: peer}
+			links[peer] = l
+			s.links = append(s.links, l)
+		}
+		return l
+	}
+	halo(init, init.Index, func(ghost image.Point, o int, src image.Point) {
+		if o == init.Index {
+			s.copies = append(s.copies, ghostCopy{ghost, src})
+		} else {
+			l := link(o)
+			l.recv = append(l.recv, ghost)
+		}
+	})
+	for j := range init.Tiles {
+		if j == init.Index {
+			continue
+		}
+		halo(init, j, func(_ image.Point, o int, src image.Point) {
+			if o == init.Index {
+				l := link(j)
+				l.send = append(l.send, src)
+			}
+		})
+	}
+
+	waiting := 0
+	for _, l := range s.links {
+		l.wbuf, l.rbuf = make([]byte, len(l.send)), make([]byte, len(l.recv))
+		if l.peer < init.Index {
+			waiting++
+			continue
+		}
+		conn, err := net.DialTimeout("tcp", init.Peers[l.peer], linkTimeout)
+		if err != nil {
+			s.close()
+			return fmt.Errorf("gogol: worker %d: %v", init.Index, err)
+		}
+		l.conn = conn
+		hello := make([]byte, 13)
+		hello[0] = connPeer
+		binary.BigEndian.PutUint64(hello[1:], init.Session)
+		binary.BigEndian.PutUint32(hello[9:], uint32(init.Index))
+		conn.SetWriteDeadline(time.Now().Add(linkTimeout))
+		if _, err := conn.Write(hello); err != nil {
+			s.close()
+			return fmt.Errorf("gogol: worker %d: %v", init.Index, err)
+		}
+	}
+	incoming := s.peers.get(init.Session)
+	defer s.peers.remove(init.Session)
+	timeout := time.NewTimer(linkTimeout)
+	defer timeout.Stop()
+	for waiting > 0 {
+		select {
+		case p := <-incoming:
+			l, ok := links[p.from]
+			if !ok || p.from > init.Index || l.conn != nil {
+				p.Close()
+				s.close()
+				return fmt.Errorf("gogol: worker %d got an unexpected link from worker %d", init.Index, p.from)
+			}
+			l.conn = p.Conn
+			waiting--
+		case <-timeout.C:
+			s.close()
+			return fmt.Errorf("gogol: worker %d timed out waiting for the workers of the neighbouring tiles", init.Index)
+		}
+	}
+	s.cells = newCells(s.tile.Dx()+2, s.tile.Dy()+2)
+	s.next = newCells(s.tile.Dx()+2, s.tile.Dy()+2)
+	return nil
+}
+
+// exchange fills the ghost cells of the tile, sending the cells along its
+// edges to the neighbouring workers at the same time.
+func (s *session) exchange() error {
+	for _, c := range s.copies {
+		s.cells[c.dst.Y][c.dst.X] = s.cells[c.src.Y][c.src.X]
+	}
+	if len(s.links) == 0 {
+		return nil
+	}
+	deadline := time.Now().Add(linkTimeout)
+	errs := make(chan error, len(s.links))
+	for _, l := range s.links {
+		l.conn.SetDeadline(deadline)
+		go func() {
+			for k, p := range l.send {
+				l.wbuf[k] = 0
+				if s.cells[p.Y][p.X] {
+					l.wbuf[k] = 1
+				}
+			}
+			_, err := l.conn.Write(l.wbuf)
+			errs <- err
+		}()
+	}
+	var err error
+	for _, l := range s.links {
+		if _, rerr := io.ReadFull(l.conn, l.rbuf); rerr != nil {
+			err = errors.Join(err, fmt.Errorf("gogol: worker %d: link to worker %d: %v", s.init.Index, l.peer, rerr))
+			continue
+		}
+		for k, p := range l.recv {
+			s.cells[p.Y][p.X] = l.rbuf[k] != 0
+		}
+	}
+	for range s.links {
+		err = errors.Join(err, <-errs)
+	}
+	return err
+}
+
+// step computes the next generation of the tile.
+func (s *session) step() {
+	for i := 1; i < len(s.cells)-1; i++ {
+		for j := 1; j < len(s.cells[i])-1; j++ {
+			s.next[i][j] = updateCellPadded(s.cells, i, j, &s.rule)
+		}
+	}
+	s.cells, s.next = s.next, s.cells
+}
+
+func (s *session) population() int {
+	n := 0
+	for _, row := range s.cells[1 : len(s.cells)-1] {
+		for _, alive := range row[1 : len(row)-1] {
+			if alive {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// close closes the links to the other workers.
+func (s *session) close() {
+	for _, l := range s.links {
+		if l.conn != nil {
+			l.conn.Close()
+		}
+	}
+	s.links, s.copies = nil, nil
+}
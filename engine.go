@@ -0,0 +1,157 @@
+// Package gogol implements Conway's Game of Life with several interchangeable
+// engines (naive, padded, struct based, sparse matrix and parallel variants).
+package gogol
+
+import (
+	"fmt"
+	"image"
+	"sort"
+)
+
+// Engine is a Game of Life simulation. Cells are addressed with x as the
+// column and y as the row, like image.Image.
+type Engine interface {
+	// Step advances the simulation one generation.
+	Step()
+	// StepN advances the simulation n generations.
+	StepN(n int)
+	// Get reports whether the cell at (x, y) is alive.
+	Get(x, y int) bool
+	// Set sets the state of the cell at (x, y).
+	Set(x, y int, alive bool)
+	// Population returns the number of live cells.
+	Population() int
+	// Bounds returns the region of the plane covered by the engine.
+	Bounds() image.Rectangle
+}
+
+// World is a read-only view of cells, implemented by engines and grids.
+type World interface {
+	Get(x, y int) bool
+	Bounds() image.Rectangle
+}
+
+// StateWorld is a World whose cells have more than two states. State 0 is
+// dead and Get reports whether a cell is in any other state.
+type StateWorld interface {
+	World
+	// StateCount returns the number of cell states.
+	StateCount() int
+	// State returns the state of the cell at (x, y).
+	State(x, y int) uint8
+}
+
+// MultiState is implemented by engines that run rules with more than two
+// states per cell.
+type MultiState interface {
+	Engine
+	StateCount() int
+	State(x, y int) uint8
+	SetState(x, y int, s uint8)
+}
+
+// Concurrent is implemented by engines that split the work between several
+// goroutines.
+type Concurrent interface {
+	Workers() int
+}
+
+// Unbounded is implemented by engines that run on the infinite plane. Their
+// Bounds only cover the live cells and the size they were created with, so
+// cells can be set anywhere.
+type Unbounded interface {
+	Unbounded()
+}
+
+// Config holds the parameters used to build an engine.
+type Config struct {
+	Width    int
+	Height   int
+	Workers  int      // only used by parallel engines
+	Rule     *Rule    // Conway's Life if nil
+	Topology Topology // Plane if empty
+	Hosts    []string // worker processes of the distributed engine, started locally if empty
+}
+
+// rule returns the rule of the config.
+func (c Config) rule() Rule {
+	if c.Rule == nil {
+		return Life
+	}
+	return *c.Rule
+}
+
+// DefaultWorkers is the number of workers used when Config.Workers is not set.
+const DefaultWorkers = 4
+
+// Factory builds an engine from a config. It returns an error if the engine
+// does not support the config, such as a rule it cannot run.
+type Factory func(c Config) (Engine, error)
+
+var factories = map[string]Factory{}
+
+// supports returns an error if the rule of c needs features, such as dying
+// states or a large neighbourhood, that the engine does not have.
+func supports(name string, c Config, features int) error {
+	r := c.rule()
+	if r.features()&^features != 0 {
+		return fmt.Errorf("gogol: engine %s does not support rule %v", name, r)
+	}
+	return nil
+}
+
+// Register makes an engine available by name. It panics if the name is
+// already registered.
+func Register(name string, f Factory) {
+	if _, dup := factories[name]; dup {
+		panic("gogol: Register called twice for engine " + name)
+	}
+	factories[name] = f
+}
+
+// Engines returns the names of the registered engines in sorted order.
+func Engines() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New builds the engine registered under name.
+func New(name string, c Config) (Engine, error) {
+	f, ok := factories[name]
+	if !ok {
+		return nil, fmt.Errorf("gogol: unknown engine %q", name)
+	}
+	if c.Width <= 0 || c.Height <= 0 {
+		return nil, fmt.Errorf("gogol: invalid size %dx%d", c.Width, c.Height)
+	}
+	if c.Workers <= 0 {
+		c.Workers = DefaultWorkers
+	}
+	return f(c)
+}
+
+// newCells allocates a Ny x Nx grid of dead cells.
+func newCells(Nx, Ny int) [][]bool {
+	cells := make([][]bool, Ny)
+	for i := range cells {
+		cells[i] = make([]bool, Nx)
+	}
+	return cells
+}
+
+// countCells returns the number of live cells in a grid.
+func countCells(cells [][]bool) int {
+	n := 0
+	for i := range cells {
+		for j := range cells[i] {
+			if cells[i][j] {
+				n++
+			}
+		}
+	}
+	return n
+}
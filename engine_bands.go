@@ -0,0 +1,209 @@
+package gogol
+
+import (
+	"fmt"
+	"image"
+	"runtime"
+	"slices"
+)
+
+func init() {
+	Register("bands", func(c Config) (Engine, error) {
+		if err := supports("bands", c, featHensel); err != nil {
+			return nil, err
+		}
+		if c.Topology == CrossSurface {
+			return nil, fmt.Errorf("gogol: engine bands does not support topology %s", c.Topology)
+		}
+		return NewBands(c), nil
+	})
+}
+
+// Bands splits the world into bands of rows, one per worker. Each worker
+// owns its band padded with ghost rows and columns, and every generation
+// only exchanges its first and last rows with the workers above and below
+// over channels, sharing nothing else. The cross surface is not supported:
+// its left and right edges join rows of different bands.
+type Bands struct {
+	bands []*rowBand
+	pool  *pool
+	rule  Rule
+	topo  Topology
+	w     int
+	h     int
+}
+
+// rowBand is the part of the world owned by a worker.
+type rowBand struct {
+	cells [][]bool // rows start-1 to end, columns -1 to w
+	next  [][]bool
+	start int
+	end   int
+
+	// Rows received from the neighbours, nil beyond an open edge
+	above chan []bool
+	below chan []bool
+	// Where to send the first and last rows, nil beyond an open edge
+	up   chan []bool
+	down chan []bool
+	// Whether the rows received across the edge are mirrored
+	flipAbove bool
+	flipBelow bool
+}
+
+// NewBands returns an empty bands engine with one band per worker, or per
+// row if there are fewer rows than workers. Close stops its workers, which
+// are otherwise stopped when the engine is garbage collected.
+func NewBands(c Config) *Bands {
+	n := max(min(c.Workers, c.Height), 1)
+	b := &Bands{pool: newPool(n), rule: c.rule(), topo: c.Topology, w: c.Width, h: c.Height}
+	for i := 0; i < n; i++ {
+		start, end := band(i, n, c.Height)
+		b.bands = append(b.bands, &rowBand{
+			cells: newCells(c.Width+2, end-start+2),
+			next:  newCells(c.Width+2, end-start+2),
+			start: start,
+			end:   end,
+			above: make(chan []bool, 1),
+			below: make(chan []bool, 1),
+		})
+	}
+	wrap := c.Topology == Torus || c.Topology == Klein
+	for i, r := range b.bands {
+		if i > 0 {
+			r.up = b.bands[i-1].below
+		} else if wrap {
+			r.up = b.bands[n-1].below
+			r.flipAbove = c.Topology == Klein
+		} else {
+			r.above = nil
+		}
+		if i < n-1 {
+			r.down = b.bands[i+1].above
+		} else if wrap {
+			r.down = b.bands[0].above
+			r.flipBelow = c.Topology == Klein
+		} else {
+			r.below = nil
+		}
+	}
+	runtime.SetFinalizer(b, func(b *Bands) { b.pool.close() })
+	return b
+}
+
+// fillColumns fills the ghost columns of the band, which come from the same
+// row on every topology but the cross surface.
+func (r *rowBand) fillColumns(topo Topology) {
+	if topo == Plane || topo == "" {
+		return
+	}
+	for _, row := range r.cells[1 : len(r.cells)-1] {
+		w := len(row) - 2
+		row[0], row[w+1] = row[w], row[1]
+	}
+}
+
+// exchange fills the ghost columns of the band, sends its first and last
+// rows to its neighbours and copies the rows they send into its ghost rows.
+func (r *rowBand) exchange(topo Topology) {
+	rows := len(r.cells) - 2
+	r.fillColumns(topo)
+	if r.up != nil {
+		r.up <- r.cells[1]
+	}
+	if r.down != nil {
+		r.down <- r.cells[rows]
+	}
+	if r.above != nil {
+		copy(r.cells[0], <-r.above)
+		if r.flipAbove {
+			slices.Reverse(r.cells[0])
+		}
+	}
+	if r.below != nil {
+		copy(r.cells[rows+1], <-r.below)
+		if r.flipBelow {
+			slices.Reverse(r.cells[rows+1])
+		}
+	}
+}
+
+// step computes the next generation of the band.
+func (r *rowBand) step(rule *Rule) {
+	for i := 1; i < len(r.cells)-1; i++ {
+		for j := 1; j < len(r.cells[i])-1; j++ {
+			r.next[i][j] = updateCellPadded(r.cells, i, j, rule)
+		}
+	}
+	r.cells, r.next = r.next, r.cells
+}
+
+func (b *Bands) Step() {
+	b.StepN(1)
+}
+
+// StepN runs its generations in a single task of the pool, the workers
+// only waiting for the rows of their neighbours.
+func (b *Bands) StepN(its int) {
+	b.pool.run(func(w int) {
+		r := b.bands[w]
+		for i := 0; i < its; i++ {
+			r.exchange(b.topo)
+			r.step(&b.rule)
+		}
+	})
+}
+
+func (b *Bands) Workers() int {
+	return b.pool.workers
+}
+
+// Close stops the workers. The engine must not be stepped afterwards.
+func (b *Bands) Close() error {
+	b.pool.close()
+	return nil
+}
+
+// cell returns the band holding row y and the row of its cells.
+func (b *Bands) cell(y int) (*rowBand, int) {
+	for _, r := range b.bands {
+		if y < r.end {
+			return r, y - r.start + 1
+		}
+	}
+	return nil, 0
+}
+
+func (b *Bands) Get(x, y int) bool {
+	if x < 0 || x >= b.w || y < 0 || y >= b.h {
+		return false
+	}
+	r, i := b.cell(y)
+	return r.cells[i][x+1]
+}
+
+func (b *Bands) Set(x, y int, alive bool) {
+	if x < 0 || x >= b.w || y < 0 || y >= b.h {
+		return
+	}
+	r, i := b.cell(y)
+	r.cells[i][x+1] = alive
+}
+
+func (b *Bands) Population() int {
+	n := 0
+	for _, r := range b.bands {
+		for _, row := range r.cells[1 : len(r.cells)-1] {
+			for _, alive := range row[1 : b.w+1] {
+				if alive {
+					n++
+				}
+			}
+		}
+	}
+	return n
+}
+
+func (b *Bands) Bounds() image.Rectangle {
+	return image.Rect(0, 0, b.w, b.h)
+}
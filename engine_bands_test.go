@@ -0,0 +1,33 @@
+package gogol
+
+import "testing"
+
+func TestBandsOneRowEach(t *testing.T) {
+	// With more workers than rows every band is a single row, whose ghost
+	// rows both come from the neighbouring bands
+	for _, topo := range []Topology{Plane, Torus, Cylinder, Klein} {
+		c := Config{Width: 9, Height: 5, Workers: 8, Topology: topo}
+		b := NewBands(c)
+		if b.Workers() != 5 {
+			t.Errorf("%s: %d workers, want 5", topo, b.Workers())
+		}
+		b.Close()
+		g, err := Soup{Seed: 7, Density: 0.4}.Grid(9, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := Verify([]string{"bands"}, c, g, 60)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d != nil {
+			t.Errorf("%s: %v", topo, d)
+		}
+	}
+}
+
+func TestBandsCrossSurface(t *testing.T) {
+	if _, err := New("bands", Config{Width: 4, Height: 4, Topology: CrossSurface}); err == nil {
+		t.Error("bands runs on the cross surface")
+	}
+}
@@ -0,0 +1,267 @@
+package gogol
+
+import (
+	"image"
+	"math/bits"
+	"runtime"
+)
+
+func init() {
+	Register("bitpacked", func(c Config) (Engine, error) {
+		if err := supports("bitpacked", c, 0); err != nil {
+			return nil, err
+		}
+		return NewBitPacked(c), nil
+	})
+	Register("parallel_bitpacked", func(c Config) (Engine, error) {
+		if err := supports("parallel_bitpacked", c, 0); err != nil {
+			return nil, err
+		}
+		return NewParallelBitPacked(c), nil
+	})
+}
+
+// BitPacked stores 64 cells per word and updates them all at once: the eight
+// neighbours of every cell of a word are shifted into place and added with
+// full adders working on each bit at the same time, giving the count as four
+// bit planes. Bit x+1 of a row is column x, bits 0 and w+1 are the padding,
+// filled before each generation for wrapped topologies.
+type BitPacked struct {
+	rows  [][]uint64
+	next  [][]uint64
+	words int // words per row
+	rule  Rule
+	topo  Topology
+	w     int
+	h     int
+}
+
+// NewBitPacked returns an empty bit-packed engine.
+func NewBitPacked(c Config) *BitPacked {
+	words := (c.Width + 2 + 63) / 64
+	return &BitPacked{
+		rows:  newWords(words, c.Height+2),
+		next:  newWords(words, c.Height+2),
+		words: words,
+		rule:  c.rule(),
+		topo:  c.Topology,
+		w:     c.Width,
+		h:     c.Height,
+	}
+}
+
+func newWords(words, rows int) [][]uint64 {
+	flat := make([]uint64, words*rows)
+	out := make([][]uint64, rows)
+	for i := range out {
+		out[i] = flat[i*words : (i+1)*words]
+	}
+	return out
+}
+
+func (b *BitPacked) bit(i, j int) bool {
+	return b.rows[i][j/64]>>(j%64)&1 != 0
+}
+
+func (b *BitPacked) setBit(i, j int, alive bool) {
+	if alive {
+		b.rows[i][j/64] |= 1 << (j % 64)
+	} else {
+		b.rows[i][j/64] &^= 1 << (j % 64)
+	}
+}
+
+// fillHalo copies into the padding the cells it stands for.
+func (b *BitPacked) fillHalo() {
+	if b.topo == Plane || b.topo == "" {
+		return
+	}
+	fill := func(i, j int) {
+		x, y, ok := b.topo.wrap(j-1, i-1, b.w, b.h)
+		b.setBit(i, j, ok && b.bit(y+1, x+1))
+	}
+	for j := 0; j < b.w+2; j++ {
+		fill(0, j)
+		fill(b.h+1, j)
+	}
+	for i := 1; i <= b.h; i++ {
+		fill(i, 0)
+		fill(i, b.w+1)
+	}
+}
+
+// halfAdd returns the sum and carry bits of a + b.
+func halfAdd(a, b uint64) (uint64, uint64) {
+	return a ^ b, a & b
+}
+
+// fullAdd returns the sum and carry bits of a + b + c.
+func fullAdd(a, b, c uint64) (uint64, uint64) {
+	t := a ^ b
+	return t ^ c, a&b | t&c
+}
+
+// west returns the words of a row holding the cell on the left of each cell.
+func west(row []uint64, k int) uint64 {
+	w := row[k] << 1
+	if k > 0 {
+		w |= row[k-1] >> 63
+	}
+	return w
+}
+
+// east returns the words of a row holding the cell on the right of each cell.
+func east(row []uint64, k int) uint64 {
+	e := row[k] >> 1
+	if k+1 < len(row) {
+		e |= row[k+1] << 63
+	}
+	return e
+}
+
+// updateWord returns word k of row i in the next generation.
+func (b *BitPacked) updateWord(i, k int) uint64 {
+	up, row, down := b.rows[i-1], b.rows[i], b.rows[i+1]
+
+	// Count live neighbors, bit plane by bit plane
+	s0, c0 := fullAdd(west(up, k), up[k], east(up, k))
+	s1, c1 := fullAdd(west(row, k), east(row, k), west(down, k))
+	s2, c2 := halfAdd(down[k], east(down, k))
+	ones, c3 := fullAdd(s0, s1, s2)
+	t, c4 := fullAdd(c0, c1, c2)
+	twos, c5 := halfAdd(t, c3)
+	fours, eights := halfAdd(c4, c5)
+
+	// Apply the rule to every count
+	alive := row[k]
+	var next uint64
+	for n := 0; n <= 8; n++ {
+		if !b.rule.Birth[n] && !b.rule.Survive[n] {
+			continue
+		}
+		eq := ^uint64(0)
+		for p, plane := range [4]uint64{ones, twos, fours, eights} {
+			if n>>p&1 != 0 {
+				eq &= plane
+			} else {
+				eq &^= plane
+			}
+		}
+		if b.rule.Birth[n] {
+			next |= eq &^ alive
+		}
+		if b.rule.Survive[n] {
+			next |= eq & alive
+		}
+	}
+	return next & b.mask(k)
+}
+
+// mask returns the bits of word k that are cells of the world, not padding.
+func (b *BitPacked) mask(k int) uint64 {
+	m := ^uint64(0)
+	if k == 0 {
+		m &^= 1
+	}
+	if end := b.w + 1 - 64*k; end < 64 {
+		m &= 1<<max(end, 0) - 1
+	}
+	return m
+}
+
+// updateRows computes the rows from start to end of the next generation.
+func (b *BitPacked) updateRows(start, end int) {
+	for i := start; i < end; i++ {
+		for k := range b.next[i] {
+			b.next[i][k] = b.updateWord(i, k)
+		}
+	}
+}
+
+func (b *BitPacked) Step() {
+	b.fillHalo()
+	b.updateRows(1, b.h+1)
+	b.rows, b.next = b.next, b.rows
+}
+
+func (b *BitPacked) StepN(its int) {
+	for i := 0; i < its; i++ {
+		b.Step()
+	}
+}
+
+func (b *BitPacked) Get(x, y int) bool {
+	if x < 0 || x >= b.w || y < 0 || y >= b.h {
+		return false
+	}
+	return b.bit(y+1, x+1)
+}
+
+func (b *BitPacked) Set(x, y int, alive bool) {
+	if x < 0 || x >= b.w || y < 0 || y >= b.h {
+		return
+	}
+	b.setBit(y+1, x+1, alive)
+}
+
+func (b *BitPacked) Population() int {
+	n := 0
+	for i := 1; i <= b.h; i++ {
+		for k, word := range b.rows[i] {
+			n += bits.OnesCount64(word & b.mask(k))
+		}
+	}
+	return n
+}
+
+func (b *BitPacked) Bounds() image.Rectangle {
+	return image.Rect(0, 0, b.w, b.h)
+}
+
+// ParallelBitPacked splits the rows of a bit-packed world between a pool of
+// workers writing into the shared next generation.
+type ParallelBitPacked struct {
+	BitPacked
+	pool *pool
+}
+
+// NewParallelBitPacked returns an empty parallel bit-packed engine. Close
+// stops its workers, which are otherwise stopped when the engine is garbage
+// collected.
+func NewParallelBitPacked(c Config) *ParallelBitPacked {
+	p := &ParallelBitPacked{BitPacked: *NewBitPacked(c), pool: newPool(max(c.Workers, 1))}
+	runtime.SetFinalizer(p, func(p *ParallelBitPacked) { p.pool.close() })
+	return p
+}
+
+func (p *ParallelBitPacked) Step() {
+	p.StepN(1)
+}
+
+// StepN runs its generations in a single task of the pool, the first worker
+// swapping the buffers and filling the padding between two barriers.
+func (p *ParallelBitPacked) StepN(its int) {
+	p.fillHalo()
+	p.pool.run(func(w int) {
+		start, end := p.pool.rows(w, p.h)
+		for i := 0; i < its; i++ {
+			p.updateRows(start+1, end+1)
+			p.pool.sync()
+			if w == 0 {
+				p.rows, p.next = p.next, p.rows
+				p.fillHalo()
+			}
+			p.pool.sync()
+		}
+	})
+}
+
+func (p *ParallelBitPacked) Workers() int {
+	return p.pool.workers
+}
+
+// Close stops the workers. The engine must not be stepped afterwards.
+func (p *ParallelBitPacked) Close() error {
+	p.pool.close()
+	return nil
+}
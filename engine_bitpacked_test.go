@@ -0,0 +1,32 @@
+package gogol
+
+import "testing"
+
+func TestBitPackedWordEdges(t *testing.T) {
+	// Rows of 62 and 63 cells fill a word with their padding, and the next
+	// sizes spill into one more
+	names := []string{"bitpacked", "parallel_bitpacked"}
+	for _, topo := range topologies {
+		for _, w := range []int{61, 62, 63, 64, 65, 126, 127, 128, 129} {
+			g, err := Soup{Seed: int64(w), Density: 0.35}.Grid(w, 11)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := Config{Width: w, Height: 11, Workers: 3, Topology: topo}
+			d, err := Verify(names, c, g, 40)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d != nil {
+				t.Errorf("%s, width %d: %v", topo, w, d)
+			}
+		}
+	}
+}
+
+func TestBitPackedTotalistic(t *testing.T) {
+	r := MustParseRule("B2ce3ai/S23-k")
+	if _, err := New("bitpacked", Config{Width: 4, Height: 4, Rule: &r}); err == nil {
+		t.Error("bitpacked runs non-totalistic rules")
+	}
+}
@@ -0,0 +1,139 @@
+package gogol
+
+import (
+	"image"
+	"image/color"
+)
+
+func init() {
+	Register("structed", func(c Config) (Engine, error) {
+		if err := supports("structed", c, featHensel); err != nil {
+			return nil, err
+		}
+		return NewBoard(c), nil
+	})
+}
+
+// Board is a padded world that keeps a second buffer with the previous
+// generation. It implements fmt.Stringer and image.Image, so it can be
+// printed and encoded directly.
+type Board struct {
+	cells  [][]bool
+	_cells [][]bool
+	rule   Rule
+	topo   Topology
+	w      int
+	h      int
+}
+
+// NewBoard returns an empty board.
+func NewBoard(c Config) *Board {
+	b := &Board{rule: c.rule(), topo: c.Topology}
+	b.init(c.Width, c.Height)
+	return b
+}
+
+// implement String interface
+
+func (b *Board) String() string {
+	s := "\033[H\033[2J"
+	for i := 1; i <= b.h-2; i++ {
+		for j := 1; j <= b.w-2; j++ {
+			if b.cells[i][j] {
+				s += "@"
+			} else {
+				s += " "
+			}
+		}
+		s += "\n"
+	}
+	return s
+}
+
+// implement image.Image interface
+
+func (b *Board) ColorModel() color.Model {
+	return color.GrayModel
+}
+
+func (b *Board) Bounds() image.Rectangle {
+	return image.Rect(0, 0, b.w-2, b.h-2)
+}
+
+func (b *Board) At(x, y int) color.Color {
+	if b.cells[y+1][x+1] {
+		return color.Gray{255}
+	}
+	return color.Gray{0}
+}
+
+// methods
+
+func (b *Board) init(Nx, Ny int) {
+	b.cells = newCells(Nx+2, Ny+2)
+	b._cells = newCells(Nx+2, Ny+2)
+	b.w = Nx + 2
+	b.h = Ny + 2
+}
+
+func (b *Board) update() {
+	fillHalo(b._cells, 1, b.topo)
+	for i := 1; i <= b.h-2; i++ {
+		for j := 1; j <= b.w-2; j++ {
+			liveNeighbors := 0
+			var config, bit uint8 = 0, 1
+			for di := -1; di <= 1; di++ {
+				for dj := -1; dj <= 1; dj++ {
+					if di == 0 && dj == 0 {
+						continue
+					}
+					ni := i + di
+					nj := j + dj
+					if b._cells[ni][nj] {
+						liveNeighbors++
+						config |= bit
+					}
+					bit <<= 1
+				}
+			}
+			// Apply the rule
+			b.cells[i][j] = b.rule.NextConfig(b._cells[i][j], liveNeighbors, config)
+		}
+	}
+	for i := 1; i <= b.h-2; i++ {
+		for j := 1; j <= b.w-2; j++ {
+			b._cells[i][j] = b.cells[i][j]
+		}
+	}
+}
+
+// engine interface
+
+func (b *Board) Step() {
+	b.update()
+}
+
+func (b *Board) StepN(its int) {
+	for i := 0; i < its; i++ {
+		b.update()
+	}
+}
+
+func (b *Board) Get(x, y int) bool {
+	if x < 0 || x >= b.w-2 || y < 0 || y >= b.h-2 {
+		return false
+	}
+	return b.cells[y+1][x+1]
+}
+
+func (b *Board) Set(x, y int, alive bool) {
+	if x < 0 || x >= b.w-2 || y < 0 || y >= b.h-2 {
+		return
+	}
+	b.cells[y+1][x+1] = alive
+	b._cells[y+1][x+1] = alive
+}
+
+func (b *Board) Population() int {
+	return countCells(b.cells)
+}
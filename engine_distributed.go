@@ -0,0 +1,310 @@
+package gogol
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"image"
+	"math/rand/v2"
+	"net"
+	"runtime"
+	"sync"
+	"time"
+)
+
+func init() {
+	Register("distributed", func(c Config) (Engine, error) {
+		if err := supports("distributed", c, featHensel); err != nil {
+			return nil, err
+		}
+		return NewDistributed(c)
+	})
+}
+
+// Distributed is the coordinator of a world split into tiles held by worker
+// processes, listening at c.Hosts. Without hosts it starts c.Workers workers
+// in this process, still talking over TCP on the loopback interface, or as
+// many as can share the world in a grid of tiles. The coordinator does not
+// keep the cells: Get fetches the tile of the cell from its worker when it
+// changed, and cells set are sent with the next request. Network errors stop
+// the engine and are reported by Err.
+type Distributed struct {
+	conns     []net.Conn
+	enc       []*gob.Encoder
+	dec       []*gob.Decoder
+	tiles     []image.Rectangle // of each worker
+	listeners []net.Listener
+	pending   []cellState
+	cache     []*Grid // fetched tiles, nil if stale
+	pop       int
+	popValid  bool
+	err       error
+	w         int
+	h         int
+}
+
+// NewDistributed connects to the workers and gives each one its tile.
+func NewDistributed(c Config) (*Distributed, error) {
+	d := &Distributed{w: c.Width, h: c.Height, popValid: true}
+	hosts := c.Hosts
+	if len(hosts) == 0 {
+		n := max(min(c.Workers, c.Width*c.Height), 1)
+		for tiling(c.Width, c.Height, n) == nil {
+			n--
+		}
+		for range n {
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				d.Close()
+				return nil, err
+			}
+			d.listeners = append(d.listeners, l)
+			hosts = append(hosts, l.Addr().String())
+			go ServeWorker(l)
+		}
+	}
+	d.tiles = tiling(c.Width, c.Height, len(hosts))
+	if d.tiles == nil {
+		d.Close()
+		return nil, fmt.Errorf("gogol: %d workers cannot share a %dx%d world in a grid of tiles", len(hosts), c.Width, c.Height)
+	}
+	d.cache = make([]*Grid, len(hosts))
+	r := c.rule()
+	session := rand.Uint64()
+	for _, host := range hosts {
+		conn, err := net.DialTimeout("tcp", host, linkTimeout)
+		if err == nil {
+			conn.SetWriteDeadline(time.Now().Add(linkTimeout))
+			_, err = conn.Write([]byte{connCoordinator})
+		}
+		if err != nil {
+			d.Close()
+			return nil, err
+		}
+		d.conns = append(d.conns, conn)
+		d.enc = append(d.enc, gob.NewEncoder(conn))
+		d.dec = append(d.dec, gob.NewDecoder(conn))
+	}
+	// The workers link to their neighbours before answering, each wait
+	// bounded by linkTimeout, so a worker that takes longer is stuck
+	for _, conn := range d.conns {
+		conn.SetReadDeadline(time.Now().Add(3 * linkTimeout))
+	}
+	_, err := d.broadcast(func(i int) *request {
+		return &request{Op: "init", Init: &initRequest{
+			Session: session, Index: i, Width: c.Width, Height: c.Height,
+			Rule: r.String(), Topology: c.Topology, Tiles: d.tiles, Peers: hosts,
+		}}
+	})
+	if err != nil {
+		d.Close()
+		return nil, err
+	}
+	for _, conn := range d.conns {
+		conn.SetReadDeadline(time.Time{})
+	}
+	runtime.SetFinalizer(d, (*Distributed).Close)
+	return d, nil
+}
+
+// broadcast sends a request to every worker for which req is not nil, then
+// waits for their answers. The first worker failing stops the wait for the
+// others, which may be blocked on a link to it, and its error is returned.
+func (d *Distributed) broadcast(req func(i int) *request) ([]response, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	var to []int
+	for i, enc := range d.enc {
+		r := req(i)
+		if r == nil {
+			continue
+		}
+		d.conns[i].SetWriteDeadline(time.Now().Add(linkTimeout))
+		if err := enc.Encode(r); err != nil {
+			d.err = err
+			return nil, err
+		}
+		to = append(to, i)
+	}
+	resp := make([]response, len(d.dec))
+	var wg sync.WaitGroup
+	var abort sync.Once
+	for _, i := range to {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := d.dec[i].Decode(&resp[i])
+			if err != nil {
+				err = fmt.Errorf("gogol: worker %d: %w", i, err)
+			} else if resp[i].Err != "" {
+				err = fmt.Errorf("gogol: worker %d: %s", i, resp[i].Err)
+			}
+			if err != nil {
+				// the others only fail because of this one
+				abort.Do(func() {
+					d.err = err
+					for _, c := range d.conns {
+						c.SetReadDeadline(time.Now())
+					}
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	return resp, d.err
+}
+
+// flush sends the cells set since the last request to their workers.
+func (d *Distributed) flush() error {
+	if len(d.pending) == 0 {
+		return d.err
+	}
+	cells := make([][]cellState, len(d.conns))
+	for _, c := range d.pending {
+		i := owner(d.tiles, c.X, c.Y)
+		cells[i] = append(cells[i], c)
+	}
+	d.pending = nil
+	_, err := d.broadcast(func(i int) *request {
+		if len(cells[i]) == 0 {
+			return nil
+		}
+		return &request{Op: "set", Cells: cells[i]}
+	})
+	return err
+}
+
+func (d *Distributed) Step() {
+	d.StepN(1)
+}
+
+// StepN runs its generations with a single request to every worker.
+func (d *Distributed) StepN(its int) {
+	if d.flush() != nil {
+		return
+	}
+	resp, err := d.broadcast(func(int) *request { return &request{Op: "step", Steps: its} })
+	if err != nil {
+		return
+	}
+	d.pop = 0
+	for _, r := range resp {
+		d.pop += r.Population
+	}
+	d.popValid = true
+	clear(d.cache)
+}
+
+// fetch copies the tiles for which want is true and that changed since they
+// were last fetched from their workers.
+func (d *Distributed) fetch(want func(i int) bool) error {
+	if err := d.flush(); err != nil {
+		return err
+	}
+	resp, err := d.broadcast(func(i int) *request {
+		if d.cache[i] != nil || !want(i) {
+			return nil
+		}
+		return &request{Op: "gather"}
+	})
+	if err != nil {
+		return err
+	}
+	for i, t := range d.tiles {
+		if d.cache[i] != nil || !want(i) {
+			continue
+		}
+		g := NewGrid(t.Dx(), t.Dy())
+		for y, row := range resp[i].Rows {
+			copy(g.Cells[y], row)
+		}
+		d.cache[i] = g
+	}
+	return nil
+}
+
+// Gather returns the whole world, copied from the workers.
+func (d *Distributed) Gather() (*Grid, error) {
+	if err := d.fetch(func(int) bool { return true }); err != nil {
+		return nil, err
+	}
+	g := NewGrid(d.w, d.h)
+	for i, t := range d.tiles {
+		for y, row := range d.cache[i].Cells {
+			copy(g.Cells[t.Min.Y+y][t.Min.X:], row)
+		}
+	}
+	return g, nil
+}
+
+func (d *Distributed) Get(x, y int) bool {
+	if x < 0 || x >= d.w || y < 0 || y >= d.h {
+		return false
+	}
+	o := owner(d.tiles, x, y)
+	if d.cache[o] == nil && d.fetch(func(i int) bool { return i == o }) != nil {
+		return false
+	}
+	t := d.tiles[o]
+	return d.cache[o].Get(x-t.Min.X, y-t.Min.Y)
+}
+
+// Set records the state of a cell, sent to its worker with the next request.
+func (d *Distributed) Set(x, y int, alive bool) {
+	if x < 0 || x >= d.w || y < 0 || y >= d.h {
+		return
+	}
+	d.pending = append(d.pending, cellState{x, y, alive})
+	d.popValid = false
+	if o := owner(d.tiles, x, y); d.cache[o] != nil {
+		t := d.tiles[o]
+		d.cache[o].Set(x-t.Min.X, y-t.Min.Y, alive)
+	}
+}
+
+func (d *Distributed) Population() int {
+	if d.popValid || d.flush() != nil {
+		return d.pop
+	}
+	resp, err := d.broadcast(func(int) *request { return &request{Op: "population"} })
+	if err != nil {
+		return d.pop
+	}
+	d.pop = 0
+	for _, r := range resp {
+		d.pop += r.Population
+	}
+	d.popValid = true
+	return d.pop
+}
+
+func (d *Distributed) Bounds() image.Rectangle {
+	return image.Rect(0, 0, d.w, d.h)
+}
+
+func (d *Distributed) Workers() int {
+	return len(d.conns)
+}
+
+// Err returns the first error talking to the workers.
+func (d *Distributed) Err() error {
+	return d.err
+}
+
+// Close disconnects from the workers and stops the ones started by the
+// engine.
+func (d *Distributed) Close() error {
+	var err error
+	for _, c := range d.conns {
+		err = errors.Join(err, c.Close())
+	}
+	for _, l := range d.listeners {
+		err = errors.Join(err, l.Close())
+	}
+	d.conns, d.listeners = nil, nil
+	if d.err == nil {
+		d.err = net.ErrClosed
+	}
+	return err
+}
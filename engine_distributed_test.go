@@ -0,0 +1,312 @@
+package gogol
+
+import (
+	"bufio"
+	"image"
+	"net"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTiling(t *testing.T) {
+	for _, size := range [][2]int{{1, 1}, {1, 7}, {7, 1}, {5, 13}, {12, 12}, {40, 3}} {
+		w, h := size[0], size[1]
+		for n := 1; n <= 13; n++ {
+			tiles := tiling(w, h, n)
+			if tiles == nil {
+				// only when no grid of n tiles fits
+				for c := 1; c <= n; c++ {
+					if n%c == 0 && c <= w && n/c <= h {
+						t.Fatalf("%dx%d, %d workers: no tiling, but %dx%d fits", w, h, n, c, n/c)
+					}
+				}
+				continue
+			}
+			if len(tiles) != n {
+				t.Fatalf("%dx%d: %d tiles for %d workers", w, h, len(tiles), n)
+			}
+			area := 0
+			for i, r := range tiles {
+				if r.Empty() || !r.In(image.Rect(0, 0, w, h)) {
+					t.Fatalf("%dx%d, %d workers: tile %d is %v", w, h, n, i, r)
+				}
+				area += r.Dx() * r.Dy()
+			}
+			// tiles inside the world covering its area cover it all if they
+			// do not overlap, and each cell has a single owner
+			if area != w*h {
+				t.Fatalf("%dx%d, %d workers: tiles cover %d cells", w, h, n, area)
+			}
+			for y := 0; y < h; y++ {
+				for x := 0; x < w; x++ {
+					if o := owner(tiles, x, y); !image.Pt(x, y).In(tiles[o]) {
+						t.Fatalf("%dx%d, %d workers: (%d, %d) owned by tile %v", w, h, n, x, y, tiles[o])
+					}
+				}
+			}
+		}
+	}
+	// 12 workers split a square world in 3x4 tiles, not 12 bands
+	if tiles := tiling(12, 12, 12); tiles[0].Dx() < 3 || tiles[0].Dy() < 3 {
+		t.Errorf("12x12, 12 workers: first tile %v", tiles[0])
+	}
+}
+
+func TestDistributedTiles(t *testing.T) {
+	// Layouts of 2x2, 3x2, 3x3 and 4x1 tiles, the corners of each tile
+	// coming from a diagonal neighbour or, across flipped edges, from tiles
+	// anywhere in the world
+	for _, topo := range topologies {
+		for _, c := range []Config{
+			{Width: 8, Height: 8, Workers: 4},
+			{Width: 11, Height: 7, Workers: 6},
+			{Width: 9, Height: 11, Workers: 9},
+			{Width: 13, Height: 2, Workers: 4},
+		} {
+			c.Topology = topo
+			g, err := Soup{Seed: 3, Density: 0.4}.Grid(c.Width, c.Height)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d, err := Verify([]string{"distributed"}, c, g, 40)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d != nil {
+				t.Errorf("%s, %dx%d, %d workers: %v", topo, c.Width, c.Height, c.Workers, d)
+			}
+		}
+	}
+}
+
+func TestDistributedGetOneTile(t *testing.T) {
+	d, err := NewDistributed(Config{Width: 8, Height: 8, Workers: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	d.Set(6, 6, true)
+	d.Step()
+	if d.Get(6, 6) {
+		t.Error("a lonely cell survived")
+	}
+	for i, g := range d.cache {
+		if (g != nil) != (i == 3) {
+			t.Errorf("tile %d fetched: %t", i, g != nil)
+		}
+	}
+}
+
+// silentWorker accepts connections and never answers them.
+func silentWorker(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, c := range conns {
+				c.Close()
+			}
+		}()
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, c)
+		}
+	}()
+	return l.Addr().String()
+}
+
+// localWorker serves a worker until the test ends.
+func localWorker(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go ServeWorker(l)
+	return l.Addr().String()
+}
+
+// workerProcess starts a gogol worker process listening on a free port
+// until the test ends, and returns its address.
+func workerProcess(t *testing.T, bin string) string {
+	cmd := exec.Command(bin, "worker", "-listen", "127.0.0.1:0")
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	s := bufio.NewScanner(out)
+	if !s.Scan() {
+		t.Fatalf("worker exited: %v", s.Err())
+	}
+	addr, ok := strings.CutPrefix(s.Text(), "worker listening on ")
+	if !ok {
+		t.Fatalf("worker printed %q", s.Text())
+	}
+	return addr
+}
+
+func TestDistributedProcesses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds cmd/gogol")
+	}
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	bin := filepath.Join(t.TempDir(), "gogol")
+	if out, err := exec.Command(gobin, "build", "-o", bin, "./cmd/gogol").CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	hosts := []string{workerProcess(t, bin), workerProcess(t, bin)}
+	for _, topo := range []Topology{Plane, Torus} {
+		c := Config{Width: 20, Height: 15, Topology: topo, Hosts: hosts}
+		g, err := Soup{Seed: 5, Density: 0.4}.Grid(c.Width, c.Height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := Verify([]string{"distributed"}, c, g, 30)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d != nil {
+			t.Errorf("%s: %v", topo, d)
+		}
+	}
+}
+
+func TestDistributedStuckWorker(t *testing.T) {
+	defer func(d time.Duration) { linkTimeout = d }(linkTimeout)
+	linkTimeout = 200 * time.Millisecond
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing := closed.Addr().String()
+	closed.Close()
+
+	tests := []struct {
+		name  string
+		hosts []string
+	}{
+		{"missing", []string{localWorker(t), missing}},
+		// the first worker dials the silent one, which never answers
+		{"silent second", []string{localWorker(t), silentWorker(t)}},
+		// the second worker waits for a link from the silent one
+		{"silent first", []string{silentWorker(t), localWorker(t)}},
+	}
+	for _, test := range tests {
+		start := time.Now()
+		d, err := NewDistributed(Config{Width: 8, Height: 8, Hosts: test.hosts})
+		if err == nil {
+			d.Close()
+			t.Errorf("%s: no error", test.name)
+		}
+		if s := time.Since(start); s > 10*linkTimeout {
+			t.Errorf("%s: failed after %v", test.name, s)
+		}
+	}
+}
+
+// dyingListener records the connections it accepts so a test can drop them
+// all, as if the worker process had died.
+type dyingListener struct {
+	net.Listener
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func (l *dyingListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err == nil {
+		l.mu.Lock()
+		l.conns = append(l.conns, c)
+		l.mu.Unlock()
+	}
+	return c, err
+}
+
+func (l *dyingListener) die() {
+	l.Close()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, c := range l.conns {
+		c.Close()
+	}
+}
+
+func TestDistributedWorkerDies(t *testing.T) {
+	defer func(d time.Duration) { linkTimeout = d }(linkTimeout)
+	linkTimeout = 200 * time.Millisecond
+
+	// A worker dying leaves its neighbours waiting for its edge cells
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := &dyingListener{Listener: inner}
+	go ServeWorker(l)
+	d, err := NewDistributed(Config{Width: 8, Height: 8, Topology: Torus, Hosts: []string{localWorker(t), l.Addr().String(), localWorker(t)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	d.Step()
+	if err := d.Err(); err != nil {
+		t.Fatal(err)
+	}
+	l.die()
+	done := make(chan struct{})
+	go func() {
+		d.Step()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the coordinator hangs after a worker died")
+	}
+	if err := d.Err(); err == nil || !strings.Contains(err.Error(), "worker 1") {
+		t.Errorf("got %v after worker 1 died", err)
+	}
+}
+
+func TestDistributedCloseStopsWorkers(t *testing.T) {
+	before := runtime.NumGoroutine()
+	for i := 0; i < 10; i++ {
+		e, err := New("distributed", Config{Width: 9, Height: 9, Workers: 4, Topology: Torus})
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.StepN(3)
+		if err := e.(*Distributed).Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("%d goroutines left running, %d before", n, before)
+	}
+}
@@ -0,0 +1,119 @@
+package gogol
+
+import (
+	"image"
+	"math"
+
+	"github.com/juansensio/gogol/fft"
+)
+
+func init() {
+	Register("fft", func(c Config) (Engine, error) {
+		if err := supports("fft", c, featLarge); err != nil {
+			return nil, err
+		}
+		return NewFFT(c), nil
+	})
+}
+
+// FFT counts neighbours by convolving the world with the stencil of the rule
+// through fast Fourier transforms, so its cost grows with the area of the
+// world but not with the one of the neighbourhood. The world is padded with
+// as many cells as the radius, filled before each generation for wrapped
+// topologies, and placed in a grid whose sides are powers of two large enough
+// for the convolution not to wrap the padding onto the world.
+type FFT struct {
+	grid   []float64   // the padded world, row by row with the width of the convolution
+	cells  [][]float64 // rows of the padded world in grid
+	sums   []float64   // live neighbours of every cell of grid
+	conv   *fft.Convolution
+	stride int // width of grid
+	table  [2][]int
+	pad    int
+	topo   Topology
+	w      int
+	h      int
+}
+
+// NewFFT returns an empty FFT engine. Rules without Larger than Life
+// parameters are run with the radius 1 Moore neighbourhood.
+func NewFFT(c Config) *FFT {
+	r := c.rule()
+	l := r.LtL()
+	if l == nil {
+		l = &LtL{Radius: 1, Neighborhood: Moore}
+	}
+	pad := l.Radius
+	W, H := fft.Size(c.Width+2*pad), fft.Size(c.Height+2*pad)
+	var kernel []fft.Tap
+	for _, d := range l.Neighborhood.Stencil(l.Radius, l.Middle) {
+		kernel = append(kernel, fft.Tap{X: d.X, Y: d.Y, Weight: 1})
+	}
+	e := &FFT{
+		grid:   make([]float64, W*H),
+		sums:   make([]float64, W*H),
+		conv:   fft.NewConvolution(W, H, kernel),
+		stride: W,
+		table:  ruleTable(r),
+		pad:    pad,
+		topo:   c.Topology,
+		w:      c.Width,
+		h:      c.Height,
+	}
+	for i := 0; i < c.Height+2*pad; i++ {
+		e.cells = append(e.cells, e.grid[i*W:i*W+c.Width+2*pad])
+	}
+	return e
+}
+
+func (e *FFT) Step() {
+	fillHalo(e.cells, e.pad, e.topo)
+	// Only the rows of the padded world are transformed, and the rows up to
+	// the last one of the world transformed back
+	e.conv.Apply(e.grid[:(e.h+2*e.pad)*e.stride], e.sums[:(e.h+e.pad)*e.stride])
+	// apply rules
+	for i := e.pad; i < e.h+e.pad; i++ {
+		row := e.cells[i]
+		for j := e.pad; j < e.w+e.pad; j++ {
+			n := int(math.Round(e.sums[i*e.stride+j]))
+			row[j] = float64(e.table[int(row[j])][n])
+		}
+	}
+}
+
+func (e *FFT) StepN(its int) {
+	for i := 0; i < its; i++ {
+		e.Step()
+	}
+}
+
+func (e *FFT) Get(x, y int) bool {
+	if x < 0 || x >= e.w || y < 0 || y >= e.h {
+		return false
+	}
+	return e.cells[y+e.pad][x+e.pad] == 1
+}
+
+func (e *FFT) Set(x, y int, alive bool) {
+	if x < 0 || x >= e.w || y < 0 || y >= e.h {
+		return
+	}
+	e.cells[y+e.pad][x+e.pad] = 0
+	if alive {
+		e.cells[y+e.pad][x+e.pad] = 1
+	}
+}
+
+func (e *FFT) Population() int {
+	n := 0
+	for _, row := range e.cells[e.pad : e.h+e.pad] {
+		for _, c := range row[e.pad : e.w+e.pad] {
+			n += int(c)
+		}
+	}
+	return n
+}
+
+func (e *FFT) Bounds() image.Rectangle {
+	return image.Rect(0, 0, e.w, e.h)
+}
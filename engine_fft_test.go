@@ -0,0 +1,32 @@
+package gogol
+
+import "testing"
+
+func TestFFTWideRules(t *testing.T) {
+	// Worlds whose padded sides are just over a power of two, and radii
+	// reaching across most of the world
+	rules := []string{
+		"R5,C0,M1,S34..58,B34..45,NM",
+		"R3,C0,M0,S2..6,B3..5,NN",
+		"R4,C0,M1,S15..30,B12..20,NC",
+	}
+	for _, rule := range rules {
+		r := MustParseRule(rule)
+		for _, topo := range topologies {
+			for _, size := range [][2]int{{7, 9}, {23, 6}, {31, 33}} {
+				c := Config{Width: size[0], Height: size[1], Rule: &r, Topology: topo}
+				g, err := Soup{Seed: 5, Density: 0.45}.Grid(size[0], size[1])
+				if err != nil {
+					t.Fatal(err)
+				}
+				d, err := Verify([]string{"fft"}, c, g, 15)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if d != nil {
+					t.Errorf("%s, %s, %dx%d: %v", rule, topo, size[0], size[1], d)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package gogol
+
+import "image"
+
+func init() {
+	Register("generations", func(c Config) (Engine, error) {
+		if err := supports("generations", c, featHensel|featStates); err != nil {
+			return nil, err
+		}
+		return NewGenerations(c), nil
+	})
+}
+
+// Generations is a padded world of multi-state cells, so it runs
+// Generations rules as well as every two-state rule.
+type Generations struct {
+	cells [][]uint8
+	next  [][]uint8
+	rule  Rule
+	topo  Topology
+	w     int
+	h     int
+}
+
+// NewGenerations returns an empty generations engine.
+func NewGenerations(c Config) *Generations {
+	g := &Generations{
+		cells: newStates(c.Width+2, c.Height+2),
+		next:  newStates(c.Width+2, c.Height+2),
+		rule:  c.rule(),
+		topo:  c.Topology,
+		w:     c.Width,
+		h:     c.Height,
+	}
+	if g.rule.States < 2 {
+		g.rule.States = 2
+	}
+	return g
+}
+
+// newStates allocates a Ny x Nx grid of dead cells.
+func newStates(Nx, Ny int) [][]uint8 {
+	cells := make([][]uint8, Ny)
+	for i := range cells {
+		cells[i] = make([]uint8, Nx)
+	}
+	return cells
+}
+
+func updateCellGenerations(cells [][]uint8, i int, j int, rule *Rule) uint8 {
+	// Count live neighbors, dying cells are not counted
+	liveNeighbors := 0
+	var config, bit uint8 = 0, 1
+	for di := -1; di <= 1; di++ {
+		for dj := -1; dj <= 1; dj++ {
+			if di == 0 && dj == 0 {
+				continue
+			}
+			if cells[i+di][j+dj] == 1 {
+				liveNeighbors++
+				config |= bit
+			}
+			bit <<= 1
+		}
+	}
+	// Apply the rule
+	return rule.NextState(cells[i][j], liveNeighbors, config)
+}
+
+func (g *Generations) Step() {
+	fillHalo(g.cells, 1, g.topo)
+	for i := 1; i <= g.h; i++ {
+		for j := 1; j <= g.w; j++ {
+			g.next[i][j] = updateCellGenerations(g.cells, i, j, &g.rule)
+		}
+	}
+	g.cells, g.next = g.next, g.cells
+}
+
+func (g *Generations) StepN(its int) {
+	for i := 0; i < its; i++ {
+		g.Step()
+	}
+}
+
+func (g *Generations) Get(x, y int) bool {
+	return g.State(x, y) != 0
+}
+
+func (g *Generations) Set(x, y int, alive bool) {
+	var s uint8
+	if alive {
+		s = 1
+	}
+	g.SetState(x, y, s)
+}
+
+func (g *Generations) StateCount() int {
+	return g.rule.States
+}
+
+func (g *Generations) State(x, y int) uint8 {
+	if x < 0 || x >= g.w || y < 0 || y >= g.h {
+		return 0
+	}
+	return g.cells[y+1][x+1]
+}
+
+func (g *Generations) SetState(x, y int, s uint8) {
+	if x < 0 || x >= g.w || y < 0 || y >= g.h || int(s) >= g.rule.States {
+		return
+	}
+	g.cells[y+1][x+1] = s
+}
+
+// Population returns the number of cells that are not dead, including the
+// dying ones.
+func (g *Generations) Population() int {
+	n := 0
+	for _, row := range g.cells[1 : g.h+1] {
+		for _, s := range row[1 : g.w+1] {
+			if s != 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func (g *Generations) Bounds() image.Rectangle {
+	return image.Rect(0, 0, g.w, g.h)
+}
@@ -0,0 +1,206 @@
+package gogol
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseGenerations(t *testing.T) {
+	tests := []struct {
+		in     string
+		states int
+		want   string
+	}{
+		{"B2/S/C3", 3, "B2/S/C3"},
+		{"/2/3", 3, "B2/S/C3"},
+		{"b2/s/c3", 3, "B2/S/C3"},
+		{"B2/S/G3", 3, "B2/S/C3"},
+		{"345/2/4", 4, "B2/S345/C4"},
+		{"StarWars", 4, "B2/S345/C4"},
+		{"B3/S23/C256", 256, "B3/S23/C256"},
+		{"B3/S23/C2", 2, "B3/S23"},
+	}
+	for _, tt := range tests {
+		r, err := ParseRule(tt.in)
+		if err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+			continue
+		}
+		if r.States != tt.states || r.Generations() != (tt.states > 2) || r.String() != tt.want {
+			t.Errorf("%q: %d states, generations %t, %s", tt.in, r.States, r.Generations(), r)
+		}
+	}
+	for _, s := range []string{"B2/S/C1", "B2/S/C257", "B2/S/C", "B2/S/Cx", "/2/", "/2/1", "/2/x", "1/2/3/4"} {
+		if r, err := ParseRule(s); err == nil {
+			t.Errorf("%q parsed as %v", s, r)
+		}
+	}
+}
+
+func TestNextState(t *testing.T) {
+	brain := MustParseRule("B2/S/C3")
+	starWars := MustParseRule("345/2/4")
+	tests := []struct {
+		r    Rule
+		s    uint8
+		n    int
+		want uint8
+	}{
+		{brain, 0, 2, 1},
+		{brain, 0, 3, 0},
+		{brain, 1, 2, 2}, // no survival, so live cells start dying
+		{brain, 2, 2, 0}, // and dying cells are never born
+		{starWars, 0, 2, 1},
+		{starWars, 1, 4, 1},
+		{starWars, 1, 2, 2},
+		{starWars, 2, 2, 3},
+		{starWars, 3, 2, 0},
+	}
+	for _, tt := range tests {
+		// every configuration of the count gives the same state
+		cfg := uint8(0xff >> (8 - tt.n))
+		if got := tt.r.NextState(tt.s, tt.n, cfg); got != tt.want {
+			t.Errorf("%v: state %d with %d neighbours goes to %d, want %d", tt.r, tt.s, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestBriansBrain(t *testing.T) {
+	// Two live cells die through state 2 while they give birth to the
+	// cells above and below them, which do the same a generation later
+	r := MustParseRule("/2/3")
+	want := []string{
+		"......" +
+			"......" +
+			"..11.." +
+			"......" +
+			"......",
+		"......" +
+			"..11.." +
+			"..22.." +
+			"..11.." +
+			"......",
+		"..11.." +
+			"..22.." +
+			".1..1." +
+			"..22.." +
+			"..11..",
+	}
+	for _, name := range Engines() {
+		e, err := New(name, Config{Width: 6, Height: 5, Rule: &r})
+		if err != nil {
+			continue
+		}
+		sw, ok := e.(StateWorld)
+		if !ok {
+			t.Errorf("%s runs Generations rules without states", name)
+			continue
+		}
+		e.Set(2, 2, true)
+		e.Set(3, 2, true)
+		for gen, w := range want {
+			if gen > 0 {
+				e.Step()
+			}
+			var got strings.Builder
+			for y := 0; y < 5; y++ {
+				for x := 0; x < 6; x++ {
+					got.WriteByte(".12"[sw.State(x, y)])
+				}
+			}
+			if got.String() != w {
+				t.Errorf("%s: generation %d is %q, want %q", name, gen, got.String(), w)
+			}
+		}
+		if c, ok := e.(io.Closer); ok {
+			c.Close()
+		}
+	}
+}
+
+func TestStatePalette(t *testing.T) {
+	black, white := color.RGBA{0, 0, 0, 255}, color.RGBA{255, 255, 255, 255}
+	tests := []struct {
+		n    int
+		want color.Palette
+	}{
+		{2, color.Palette{black, white}},
+		{3, color.Palette{black, white, color.RGBA{255, 160, 0, 255}}},
+		// a fade from orange to dark red
+		{5, color.Palette{black, white, color.RGBA{255, 160, 0, 255}, color.RGBA{167, 80, 0, 255}, color.RGBA{80, 0, 0, 255}}},
+	}
+	for _, tt := range tests {
+		p := StatePalette(tt.n)
+		if len(p) != len(tt.want) {
+			t.Errorf("%d states: %d colours", tt.n, len(p))
+			continue
+		}
+		for s := range p {
+			if p[s] != tt.want[s] {
+				t.Errorf("%d states: state %d is %v, want %v", tt.n, s, p[s], tt.want[s])
+			}
+		}
+	}
+}
+
+// dyingWorld returns a Star Wars world with a cell in each state.
+func dyingWorld() *Generations {
+	r := MustParseRule("345/2/4")
+	e := NewGenerations(Config{Width: 4, Height: 1, Rule: &r})
+	for s := 1; s < 4; s++ {
+		e.SetState(s, 0, uint8(s))
+	}
+	return e
+}
+
+func TestColorImage(t *testing.T) {
+	e := dyingWorld()
+	p := StatePalette(4)
+	img := ColorImage(e)
+	if img.Bounds() != image.Rect(0, 0, 4, 1) {
+		t.Fatalf("bounds %v", img.Bounds())
+	}
+	for x := 0; x < 4; x++ {
+		if img.ColorIndexAt(x, 0) != uint8(x) || img.At(x, 0) != p[x] {
+			t.Errorf("state %d drawn as %d, %v", x, img.ColorIndexAt(x, 0), img.At(x, 0))
+		}
+	}
+
+	// the same colours in PNGs
+	path := filepath.Join(t.TempDir(), "states.png")
+	if err := SavePNG(path, e); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	decoded, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for x := 0; x < 4; x++ {
+		if got := color.RGBAModel.Convert(decoded.At(x, 0)); got != p[x] {
+			t.Errorf("PNG state %d is %v, want %v", x, got, p[x])
+		}
+	}
+}
+
+func TestPrintTerminalStates(t *testing.T) {
+	var buf bytes.Buffer
+	PrintTerminal(&buf, 7, dyingWorld())
+	// dead cells are blank and the others '@' in the colour of their state
+	want := "\033[H\033[2JIteration: 7\n" +
+		" \033[38;2;255;255;255m@\033[38;2;255;160;0m@\033[38;2;80;0;0m@\033[0m\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
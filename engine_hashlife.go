@@ -0,0 +1,237 @@
+package gogol
+
+import (
+	"fmt"
+	"image"
+)
+
+func init() {
+	Register("hashlife", func(c Config) (Engine, error) {
+		if err := supports("hashlife", c, featHensel); err != nil {
+			return nil, err
+		}
+		if r := c.rule(); r.Birth[0] {
+			return nil, fmt.Errorf("gogol: engine hashlife cannot run rule %v, B0 fills the infinite plane", r)
+		}
+		if c.Topology != "" && c.Topology != Plane {
+			return nil, fmt.Errorf("gogol: engine hashlife only runs on the plane, got %s", c.Topology)
+		}
+		return NewHashLife(c), nil
+	})
+}
+
+// DefaultMaxNodes is the number of quadtree nodes HashLife stores before
+// dropping the ones no longer in use.
+const DefaultMaxNodes = 1 << 21
+
+// HashLife runs patterns on the unbounded plane with Gosper's algorithm: the
+// world is a canonical quadtree and the future of the center of every node
+// is computed once and memoized, so repetitive patterns can leap 2^k
+// generations in about the time of one.
+type HashLife struct {
+	q        *Quadtree
+	rule     Rule
+	results  map[resultKey]*Node
+	maxNodes int
+	view     image.Rectangle // configured size
+	box      *image.Rectangle
+	gen      int64
+}
+
+// resultKey identifies the center of a node advanced 2^step generations.
+type resultKey struct {
+	n    *Node
+	step int
+}
+
+// NewHashLife returns an empty HashLife engine.
+func NewHashLife(c Config) *HashLife {
+	return &HashLife{
+		q:        NewQuadtree(),
+		rule:     c.rule(),
+		results:  make(map[resultKey]*Node),
+		maxNodes: DefaultMaxNodes,
+		view:     image.Rect(0, 0, c.Width, c.Height),
+	}
+}
+
+// SetMaxNodes sets the number of nodes stored before collecting the unused
+// ones, DefaultMaxNodes if n <= 0.
+func (e *HashLife) SetMaxNodes(n int) {
+	if n <= 0 {
+		n = DefaultMaxNodes
+	}
+	e.maxNodes = n
+}
+
+// Nodes returns the number of nodes in the cache.
+func (e *HashLife) Nodes() int {
+	return e.q.Nodes()
+}
+
+// Generation returns the number of generations run.
+func (e *HashLife) Generation() int64 {
+	return e.gen
+}
+
+// centre returns the level k-1 node at the center of a level k node.
+func (e *HashLife) centre(n *Node) *Node {
+	return e.q.Node(n.NW.SE, n.NE.SW, n.SW.NE, n.SE.NW)
+}
+
+// base returns the center of a level 2 node after one generation.
+func (e *HashLife) base(n *Node) *Node {
+	var cells [4][4]bool
+	for i, c := range [4]*Node{n.NW, n.NE, n.SW, n.SE} {
+		x, y := 2*(i%2), 2*(i/2)
+		cells[y][x] = c.NW.Population != 0
+		cells[y][x+1] = c.NE.Population != 0
+		cells[y+1][x] = c.SW.Population != 0
+		cells[y+1][x+1] = c.SE.Population != 0
+	}
+	var next [4]*Node
+	for i := range next {
+		x, y := 1+i%2, 1+i/2
+		// Count live neighbors, building their configuration
+		var config, bit uint8 = 0, 1
+		count := 0
+		for _, d := range neighbourOffsets {
+			if cells[y+d[1]][x+d[0]] {
+				config |= bit
+				count++
+			}
+			bit <<= 1
+		}
+		next[i] = e.q.Leaf(e.rule.NextConfig(cells[y][x], count, config))
+	}
+	return e.q.Node(next[0], next[1], next[2], next[3])
+}
+
+// result returns the level k-1 center of a level k node advanced
+// 2^min(step, k-2) generations.
+func (e *HashLife) result(n *Node, step int) *Node {
+	step = min(step, n.Level-2)
+	key := resultKey{n, step}
+	if r, ok := e.results[key]; ok {
+		return r
+	}
+	var r *Node
+	switch {
+	case n.Population == 0:
+		r = n.NW
+	case n.Level == 2:
+		r = e.base(n)
+	default:
+		// The nine overlapping level k-1 nodes
+		q := e.q
+		n00, n01, n02 := n.NW, q.Node(n.NW.NE, n.NE.NW, n.NW.SE, n.NE.SW), n.NE
+		n10 := q.Node(n.NW.SW, n.NW.SE, n.SW.NW, n.SW.NE)
+		n11 := e.centre(n)
+		n12 := q.Node(n.NE.SW, n.NE.SE, n.SE.NW, n.SE.NE)
+		n20, n21, n22 := n.SW, q.Node(n.SW.NE, n.SE.NW, n.SW.SE, n.SE.SW), n.SE
+
+		// Advance them half the way, or not at all when stepping less than
+		// the node allows
+		half := e.result
+		if step < n.Level-2 {
+			half = func(n *Node, _ int) *Node { return e.centre(n) }
+		}
+		c00, c01, c02 := half(n00, step), half(n01, step), half(n02, step)
+		c10, c11, c12 := half(n10, step), half(n11, step), half(n12, step)
+		c20, c21, c22 := half(n20, step), half(n21, step), half(n22, step)
+
+		// and the four level k-1 nodes they form the other half
+		r = q.Node(
+			e.result(q.Node(c00, c01, c10, c11), step),
+			e.result(q.Node(c01, c02, c11, c12), step),
+			e.result(q.Node(c10, c11, c20, c21), step),
+			e.result(q.Node(c11, c12, c21, c22), step),
+		)
+	}
+	e.results[key] = r
+	return r
+}
+
+// Leap advances the world 2^k generations.
+func (e *HashLife) Leap(k int) {
+	// The pattern must stay in the center of the root, which is all that
+	// is left after the leap
+	for e.q.Root.Level < k+3 || e.centre(e.centre(e.q.Root)).Population != e.q.Root.Population {
+		e.q.expand()
+	}
+	half := 1 << (e.q.Root.Level - 2)
+	e.q.Root = e.result(e.q.Root, k)
+	e.q.Origin = e.q.Origin.Add(image.Pt(half, half))
+	e.gen += 1 << k
+	e.box = nil
+	if e.q.Nodes() > e.maxNodes {
+		e.collect()
+	}
+}
+
+// collect drops the nodes unreachable from the root and the memoized
+// results that use them.
+func (e *HashLife) collect() {
+	e.q.Collect()
+	for k, r := range e.results {
+		if !e.q.has(k.n) || !e.q.has(r) {
+			delete(e.results, k)
+		}
+	}
+}
+
+func (e *HashLife) Step() {
+	e.Leap(0)
+}
+
+// StepN advances n generations with one leap for each bit set in n.
+func (e *HashLife) StepN(n int) {
+	for k := 0; n > 0; k++ {
+		if n&1 != 0 {
+			e.Leap(k)
+		}
+		n >>= 1
+	}
+}
+
+func (e *HashLife) Get(x, y int) bool {
+	return e.q.Get(x, y)
+}
+
+// Set sets the state of a cell anywhere in the plane.
+func (e *HashLife) Set(x, y int, alive bool) {
+	e.q.Set(x, y, alive)
+	e.box = nil
+}
+
+func (e *HashLife) Population() int {
+	return e.q.Population()
+}
+
+// Bounds returns the configured size grown to hold every live cell.
+func (e *HashLife) Bounds() image.Rectangle {
+	if e.box == nil {
+		b := e.q.Bounds()
+		e.box = &b
+	}
+	return e.view.Union(*e.box)
+}
+
+// Unbounded marks the engine as an infinite plane.
+func (e *HashLife) Unbounded() {}
+
+// LoadQuadtree copies q with the top-left corner of its bounds at (x, y).
+// An empty engine takes the nodes of q as they are, so patterns far too
+// big for a grid load in the time of their distinct blocks.
+func (e *HashLife) LoadQuadtree(q *Quadtree, x, y int) {
+	off := image.Pt(x, y).Sub(q.Bounds().Min)
+	if e.Population() != 0 {
+		q.Each(q.Bounds(), func(cx, cy int) {
+			e.Set(cx+off.X, cy+off.Y, true)
+		})
+		return
+	}
+	e.q.Root = e.q.canonical(q.Root, make(map[*Node]*Node))
+	e.q.Origin = q.Origin.Add(off)
+	e.box = nil
+}
@@ -0,0 +1,64 @@
+package gogol
+
+import "testing"
+
+func TestHashLifeGlider(t *testing.T) {
+	checkGlider(t, "hashlife", 1<<12)
+}
+
+// sameWorld reports whether a and b have the same live cells in the union of
+// their bounds.
+func sameWorld(a, b World) bool {
+	r := a.Bounds().Union(b.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if a.Get(x, y) != b.Get(x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestHashLifeLeap(t *testing.T) {
+	// A soup leaping several powers of two at once matches one stepped a
+	// generation at a time, also when the nodes are collected on the way
+	g, err := Soup{Seed: 3, Density: 0.4}.Grid(16, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rule := range []string{"B3/S23", "B36/S23", "B2ce3ai/S23-k"} {
+		r := MustParseRule(rule)
+		c := Config{Width: 16, Height: 16, Rule: &r}
+		want := NewSparse(c)
+		Load(want, g)
+		for _, maxNodes := range []int{0, 500} {
+			e := NewHashLife(c)
+			e.SetMaxNodes(maxNodes)
+			Load(e, g)
+			e.StepN(300)
+			if e.Generation() != 300 {
+				t.Errorf("%s: at generation %d, want 300", rule, e.Generation())
+			}
+			if maxNodes == 0 {
+				want.StepN(300)
+			}
+			if !sameWorld(e, want) {
+				t.Errorf("%s, %d nodes: differs from sparse after 300 generations", rule, maxNodes)
+			}
+		}
+	}
+}
+
+func TestHashLifeLoadQuadtree(t *testing.T) {
+	p := &Pattern{Grid: glider()}
+	e := NewHashLife(Config{Width: 8, Height: 8})
+	LoadPattern(e, &Pattern{Tree: QuadtreeOf(p.Grid)}, 2, 3)
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 3; x++ {
+			if e.Get(x+2, y+3) != p.Get(x, y) {
+				t.Fatalf("cell (%d, %d) loaded wrong", x+2, y+3)
+			}
+		}
+	}
+}
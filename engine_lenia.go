@@ -0,0 +1,183 @@
+package gogol
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/juansensio/gogol/fft"
+)
+
+func init() {
+	Register("lenia", func(c Config) (Engine, error) {
+		if err := supports("lenia", c, featContinuous); err != nil {
+			return nil, err
+		}
+		if r := c.rule(); r.Continuous() == nil {
+			return nil, fmt.Errorf("gogol: engine lenia does not support rule %v", r)
+		}
+		return NewLenia(c), nil
+	})
+}
+
+// Lenia runs continuous rules, Lenia and SmoothLife, on float32 cells. The
+// potentials are convolutions of the world with the kernels of the rule,
+// computed with fast Fourier transforms as in the FFT engine: the world is
+// padded with as many cells as the radius, filled before each time step for
+// wrapped topologies.
+//
+// As a MultiState engine it has 256 states, the values scaled to 0-255 and
+// drawn with a colour map, and a cell is alive if its state is not 0.
+type Lenia struct {
+	cells  [][]float32 // the padded world
+	grid   []float64   // the padded world, row by row with the width of the convolutions
+	sums   [][]float64 // potentials of every cell of grid, one per kernel
+	u      []float64   // potentials of a cell
+	convs  []*fft.Convolution
+	rule   *Continuous
+	colors color.Palette
+	stride int // width of grid
+	pad    int
+	topo   Topology
+	w      int
+	h      int
+}
+
+// NewLenia returns an empty Lenia engine coloured with the viridis colour
+// map. The rule of c must be continuous.
+func NewLenia(c Config) *Lenia {
+	r := c.rule()
+	cont := r.Continuous()
+	pad := cont.Radius
+	W, H := fft.Size(c.Width+2*pad), fft.Size(c.Height+2*pad)
+	colors, _ := ColorMap("viridis", 256)
+	e := &Lenia{
+		cells:  newFloats(c.Width+2*pad, c.Height+2*pad),
+		grid:   make([]float64, W*H),
+		rule:   cont,
+		colors: colors,
+		stride: W,
+		pad:    pad,
+		topo:   c.Topology,
+		w:      c.Width,
+		h:      c.Height,
+	}
+	for _, k := range cont.kernels() {
+		e.convs = append(e.convs, fft.NewConvolution(W, H, k))
+		e.sums = append(e.sums, make([]float64, W*H))
+	}
+	e.u = make([]float64, len(e.convs))
+	return e
+}
+
+func newFloats(w, h int) [][]float32 {
+	flat := make([]float32, w*h)
+	out := make([][]float32, h)
+	for i := range out {
+		out[i] = flat[i*w : (i+1)*w]
+	}
+	return out
+}
+
+func (e *Lenia) Step() {
+	fillHalo(e.cells, e.pad, e.topo)
+	for i, row := range e.cells {
+		for j, v := range row {
+			e.grid[i*e.stride+j] = float64(v)
+		}
+	}
+	// Only the rows of the padded world are transformed, and the rows up to
+	// the last one of the world transformed back
+	rows := len(e.cells) * e.stride
+	for k, conv := range e.convs {
+		conv.Apply(e.grid[:rows], e.sums[k][:(e.h+e.pad)*e.stride])
+	}
+	for i := e.pad; i < e.h+e.pad; i++ {
+		row := e.cells[i]
+		for j := e.pad; j < e.w+e.pad; j++ {
+			for k := range e.u {
+				e.u[k] = e.sums[k][i*e.stride+j]
+			}
+			row[j] = float32(e.rule.next(float64(row[j]), e.u))
+		}
+	}
+}
+
+func (e *Lenia) StepN(its int) {
+	for i := 0; i < its; i++ {
+		e.Step()
+	}
+}
+
+// Value returns the value of the cell at (x, y), between 0 and 1.
+func (e *Lenia) Value(x, y int) float32 {
+	if x < 0 || x >= e.w || y < 0 || y >= e.h {
+		return 0
+	}
+	return e.cells[y+e.pad][x+e.pad]
+}
+
+// SetValue sets the value of the cell at (x, y), clamped between 0 and 1.
+func (e *Lenia) SetValue(x, y int, v float32) {
+	if x < 0 || x >= e.w || y < 0 || y >= e.h {
+		return
+	}
+	e.cells[y+e.pad][x+e.pad] = min(max(v, 0), 1)
+}
+
+func (e *Lenia) Get(x, y int) bool {
+	return e.State(x, y) != 0
+}
+
+func (e *Lenia) Set(x, y int, alive bool) {
+	e.SetValue(x, y, 0)
+	if alive {
+		e.SetValue(x, y, 1)
+	}
+}
+
+func (e *Lenia) StateCount() int {
+	return 256
+}
+
+// State returns the value of the cell at (x, y) scaled to 0-255.
+func (e *Lenia) State(x, y int) uint8 {
+	return uint8(math.Round(float64(e.Value(x, y)) * 255))
+}
+
+func (e *Lenia) SetState(x, y int, s uint8) {
+	e.SetValue(x, y, float32(s)/255)
+}
+
+// Colors returns the colour map of the states.
+func (e *Lenia) Colors() color.Palette {
+	return e.colors
+}
+
+func (e *Lenia) Population() int {
+	n := 0
+	for _, row := range e.cells[e.pad : e.h+e.pad] {
+		for _, v := range row[e.pad : e.w+e.pad] {
+			if v >= 0.5/255 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Mass returns the sum of the values of the cells.
+func (e *Lenia) Mass() float64 {
+	m := 0.0
+	for _, row := range e.cells[e.pad : e.h+e.pad] {
+		for _, v := range row[e.pad : e.w+e.pad] {
+			m += float64(v)
+		}
+	}
+	return m
+}
+
+func (e *Lenia) Bounds() image.Rectangle {
+	return image.Rect(0, 0, e.w, e.h)
+}
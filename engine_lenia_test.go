@@ -0,0 +1,199 @@
+package gogol
+
+import (
+	"image/color"
+	"math"
+	"os"
+	"strings"
+	"testing"
+)
+
+// centre returns the centre of mass of the values of e.
+func centre(e *Lenia) (x, y float64) {
+	r := e.Bounds()
+	for j := r.Min.Y; j < r.Max.Y; j++ {
+		for i := r.Min.X; i < r.Max.X; i++ {
+			v := float64(e.Value(i, j))
+			x += v * float64(i)
+			y += v * float64(j)
+		}
+	}
+	m := e.Mass()
+	return x / m, y / m
+}
+
+func TestOrbium(t *testing.T) {
+	f, err := os.Open("patterns/orbium.rle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	p, err := ReadRLE(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ParseRule(p.Rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewLenia(Config{Width: 64, Height: 64, Rule: &r, Topology: Torus})
+	LoadPattern(e, p, 22, 22)
+	mass := e.Mass()
+	x0, y0 := centre(e)
+	// about a cell every 10 time steps, staying clear of the edges
+	e.StepN(100)
+	if m := e.Mass(); math.Abs(m-mass) > 0.2*mass {
+		t.Errorf("mass went from %.1f to %.1f", mass, m)
+	}
+	x1, y1 := centre(e)
+	if d := math.Hypot(x1-x0, y1-y0); d < 3 || d > 30 {
+		t.Errorf("moved %.1f cells in 100 steps", d)
+	}
+}
+
+func TestSmoothLifeValues(t *testing.T) {
+	r := MustParseRule("SmoothLife")
+	e := NewLenia(Config{Width: 50, Height: 45, Rule: &r, Topology: Torus})
+	g, err := Soup{Seed: 1, Density: 0.5}.Grid(50, 45)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Load(e, g)
+	e.StepN(5)
+	for y := 0; y < 45; y++ {
+		for x := 0; x < 50; x++ {
+			if v := e.Value(x, y); v < 0 || v > 1 || math.IsNaN(float64(v)) {
+				t.Fatalf("cell (%d, %d) is %g", x, y, v)
+			}
+		}
+	}
+}
+
+func TestParseContinuous(t *testing.T) {
+	tests := map[string]string{
+		"Orbium":     "R=13;T=10;b=1;m=0.15;s=0.015;kn=1;gn=1",
+		"SmoothLife": "ra=21;ri=7;b1=0.278;b2=0.365;d1=0.267;d2=0.445;an=0.028;am=0.147;dt=0.1",
+		"R=18;T=10;b=1,5/12,2/3;m=0.26;s=0.036;kn=2;gn=2": "R=18;T=10;b=1,0.4166666666666667,0.6666666666666666;m=0.26;s=0.036;kn=2;gn=2",
+		"r=5; dt=0.3; m=0.2; s=0.05":                      "R=5;dt=0.3;b=1;m=0.2;s=0.05;kn=1;gn=1",
+		"ra=9;dt=1":                                       "ra=9;ri=3;b1=0.278;b2=0.365;d1=0.267;d2=0.445;an=0.028;am=0.147;dt=1",
+	}
+	for in, want := range tests {
+		r, err := ParseRule(in)
+		if err != nil {
+			t.Errorf("%s: %v", in, err)
+			continue
+		}
+		if r.Continuous() == nil {
+			t.Errorf("%s: not continuous", in)
+			continue
+		}
+		if got := r.String(); got != want {
+			t.Errorf("%s: got %s, want %s", in, got, want)
+		}
+		// the string reads back as the same rule
+		if again, err := ParseRule(r.String()); err != nil || again.String() != want {
+			t.Errorf("%s: %s reads back as %v, %v", in, want, again, err)
+		}
+	}
+}
+
+func TestParseContinuousErrors(t *testing.T) {
+	for _, s := range []string{
+		"R=13;T=10;b=1;s=0.015",     // no m
+		"R=13;T=10;b=1;m=0.15",      // no s
+		"T=10;b=1;m=0.15;s=0.015",   // no radius
+		"R=0;m=0.15;s=0.015",        // radius too small
+		"R=101;m=0.15;s=0.015",      // radius too large
+		"R=13;m=0.15;s=0",           // flat growth
+		"R=13;m=0.15;s=0.015;T=0.5", // time step of 2
+		"R=13;m=0.15;s=0.015;dt=0",  // no time step
+		"R=13;m=0.15;s=0.015;kn=3",  // unknown core
+		"R=13;m=0.15;s=0.015;gn=0",  // unknown growth
+		"R=13;m=0.15;s=0.015;b=1/0", // peak divided by zero
+		"R=13;m=0.15;s=0.015;x=1",   // unknown field
+		"R=13;m=0.15;;s=0.015",      // empty field
+		"ra=21;ri=7;b1=abc",         // not a number
+	} {
+		if r, err := ParseRule(s); err == nil {
+			t.Errorf("%s: parsed as %v", s, r)
+		}
+	}
+}
+
+func TestKernels(t *testing.T) {
+	for _, s := range []string{"Orbium", "SmoothLife", "R=18;T=10;b=1,5/12,2/3;m=0.26;s=0.036;kn=2;gn=2", "R=1;m=0.2;s=0.05"} {
+		r := MustParseRule(s)
+		c := r.Continuous()
+		kernels := c.kernels()
+		want := 1
+		if c.Smooth != nil {
+			want = 2
+		}
+		if len(kernels) != want {
+			t.Errorf("%s: %d kernels", s, len(kernels))
+		}
+		for k, kernel := range kernels {
+			sum := 0.0
+			for _, tap := range kernel {
+				if tap.Weight < 0 || max(abs(tap.X), abs(tap.Y)) > c.Radius {
+					t.Errorf("%s: kernel %d has %v", s, k, tap)
+				}
+				sum += tap.Weight
+			}
+			if math.Abs(sum-1) > 1e-9 {
+				t.Errorf("%s: kernel %d sums to %g", s, k, sum)
+			}
+		}
+	}
+}
+
+func abs(x int) int {
+	return max(x, -x)
+}
+
+func TestColorMap(t *testing.T) {
+	for _, name := range ColorMaps() {
+		p, err := ColorMap(name, 256)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the ends are the first and last colours of the map
+		stops, err := ParsePalette(strings.Join(colorMaps[name], ","))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(p) != 256 || p[0] != stops[0] || p[255] != stops[len(stops)-1] {
+			t.Errorf("%s: %d colours from %v to %v", name, len(p), p[0], p[len(p)-1])
+		}
+	}
+	if p, err := ColorMap("gray", 3); err != nil || p[1] != (color.RGBA{128, 128, 128, 255}) {
+		t.Errorf("gray middle is %v, %v", p, err)
+	}
+	if p, err := ColorMap("gray", 1); err != nil || len(p) != 1 {
+		t.Errorf("a single colour: %v, %v", p, err)
+	}
+	if _, err := ColorMap("sepia", 256); err == nil {
+		t.Error("unknown colour map accepted")
+	}
+}
+
+func TestRecolor(t *testing.T) {
+	r := MustParseRule("Orbium")
+	e := NewLenia(Config{Width: 4, Height: 3, Rule: &r})
+	e.SetValue(1, 2, 0.5)
+	inferno, err := ColorMap("inferno", 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := Recolor(e, inferno)
+	if got := paletteOf(w); got[255] != inferno[255] {
+		t.Errorf("recoloured world has colours %v", got[255])
+	}
+	if w.State(1, 2) != 128 || w.StateCount() != 256 || w.Bounds() != e.Bounds() {
+		t.Errorf("recoloured world has state %d of %d in %v", w.State(1, 2), w.StateCount(), w.Bounds())
+	}
+	// the engine keeps its own colours
+	if viridis, _ := ColorMap("viridis", 256); paletteOf(e)[255] != viridis[255] {
+		t.Error("Recolor changed the colours of the engine")
+	}
+}
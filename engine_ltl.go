@@ -0,0 +1,184 @@
+package gogol
+
+import "image"
+
+func init() {
+	Register("ltl", func(c Config) (Engine, error) {
+		if err := supports("ltl", c, featStates|featLarge); err != nil {
+			return nil, err
+		}
+		return NewLargerThanLife(c), nil
+	})
+}
+
+// LargerThanLife runs totalistic rules with neighbourhoods of any radius,
+// counting the live cells with prefix sums instead of visiting the
+// neighbourhood of every cell: a summed-area table for Moore neighbourhoods
+// and running sums along the rows for the other shapes. The world is
+// padded with as many cells as the radius, filled before each generation
+// for wrapped topologies.
+type LargerThanLife struct {
+	cells   [][]uint8
+	next    [][]uint8
+	sums    []int32 // summed-area table or row prefix sums
+	birth   []bool
+	survive []bool
+	states  int
+	radius  int
+	middle  bool
+	moore   bool
+	spans   []int // half width of each row of the neighbourhood
+	topo    Topology
+	w       int
+	h       int
+}
+
+// NewLargerThanLife returns an empty Larger than Life engine. Rules without
+// Larger than Life parameters are run with the radius 1 Moore neighbourhood.
+func NewLargerThanLife(c Config) *LargerThanLife {
+	r := c.rule()
+	l := r.LtL()
+	if l == nil {
+		l = &LtL{Radius: 1, Neighborhood: Moore}
+	}
+	birth, survive := countTables(&r)
+	e := &LargerThanLife{
+		cells:   newStates(c.Width+2*l.Radius, c.Height+2*l.Radius),
+		next:    newStates(c.Width+2*l.Radius, c.Height+2*l.Radius),
+		sums:    make([]int32, (c.Width+2*l.Radius+1)*(c.Height+2*l.Radius+1)),
+		birth:   birth,
+		survive: survive,
+		states:  max(r.States, 2),
+		radius:  l.Radius,
+		middle:  l.Middle,
+		moore:   l.Neighborhood == Moore,
+		topo:    c.Topology,
+		w:       c.Width,
+		h:       c.Height,
+	}
+	for dy := -l.Radius; dy <= l.Radius; dy++ {
+		e.spans = append(e.spans, l.Neighborhood.span(l.Radius, dy))
+	}
+	return e
+}
+
+// prefixSums fills the sums of the live cells, each row of sums is one
+// longer than the rows of cells and starts with 0. For Moore
+// neighbourhoods sums also has a first row of zeros and adds the rows
+// above.
+func (e *LargerThanLife) prefixSums() {
+	W := e.w + 2*e.radius + 1
+	for i, row := range e.cells {
+		s := e.sums[i*W : (i+1)*W]
+		if e.moore {
+			s = e.sums[(i+1)*W : (i+2)*W]
+		}
+		var run int32
+		for j, c := range row {
+			if c == 1 {
+				run++
+			}
+			s[j+1] = run
+			if e.moore {
+				s[j+1] += e.sums[i*W+j+1]
+			}
+		}
+	}
+}
+
+// count returns the live cells counted by the cell at row i, column j of
+// the padded world.
+func (e *LargerThanLife) count(i, j int) int {
+	W := e.w + 2*e.radius + 1
+	r := e.radius
+	n := int32(0)
+	if e.moore {
+		n = e.sums[(i+r+1)*W+j+r+1] - e.sums[(i-r)*W+j+r+1] - e.sums[(i+r+1)*W+j-r] + e.sums[(i-r)*W+j-r]
+	} else {
+		for dy, w := range e.spans {
+			row := (i + dy - r) * W
+			n += e.sums[row+j+w+1] - e.sums[row+j-w]
+		}
+	}
+	if !e.middle && e.cells[i][j] == 1 {
+		n--
+	}
+	return int(n)
+}
+
+func (e *LargerThanLife) Step() {
+	fillHalo(e.cells, e.radius, e.topo)
+	e.prefixSums()
+	for i := e.radius; i < e.h+e.radius; i++ {
+		for j := e.radius; j < e.w+e.radius; j++ {
+			n := e.count(i, j)
+			switch s := e.cells[i][j]; {
+			case s == 0 && e.birth[n]:
+				e.next[i][j] = 1
+			case s == 0:
+				e.next[i][j] = 0
+			case s == 1 && e.survive[n]:
+				e.next[i][j] = 1
+			case int(s)+1 < e.states:
+				e.next[i][j] = s + 1 // start or keep dying
+			default:
+				e.next[i][j] = 0
+			}
+		}
+	}
+	e.cells, e.next = e.next, e.cells
+}
+
+func (e *LargerThanLife) StepN(its int) {
+	for i := 0; i < its; i++ {
+		e.Step()
+	}
+}
+
+func (e *LargerThanLife) Get(x, y int) bool {
+	return e.State(x, y) != 0
+}
+
+func (e *LargerThanLife) Set(x, y int, alive bool) {
+	var s uint8
+	if alive {
+		s = 1
+	}
+	e.SetState(x, y, s)
+}
+
+func (e *LargerThanLife) StateCount() int {
+	return e.states
+}
+
+func (e *LargerThanLife) State(x, y int) uint8 {
+	if x < 0 || x >= e.w || y < 0 || y >= e.h {
+		return 0
+	}
+	return e.cells[y+e.radius][x+e.radius]
+}
+
+func (e *LargerThanLife) SetState(x, y int, s uint8) {
+	if x < 0 || x >= e.w || y < 0 || y >= e.h || int(s) >= e.states {
+		return
+	}
+	e.cells[y+e.radius][x+e.radius] = s
+}
+
+// Population returns the number of cells that are not dead, including the
+// dying ones.
+func (e *LargerThanLife) Population() int {
+	n := 0
+	for _, row := range e.cells[e.radius : e.h+e.radius] {
+		for _, s := range row[e.radius : e.w+e.radius] {
+			if s != 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func (e *LargerThanLife) Bounds() image.Rectangle {
+	return image.Rect(0, 0, e.w, e.h)
+}
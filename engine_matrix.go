@@ -0,0 +1,182 @@
+package gogol
+
+import (
+	"image"
+
+	"github.com/juansensio/gogol/sparse"
+)
+
+func init() {
+	Register("matrix", func(c Config) (Engine, error) {
+		if err := supports("matrix", c, featLarge); err != nil {
+			return nil, err
+		}
+		return NewMatrix(c), nil
+	})
+	for name, format := range matrixFormats {
+		Register("matrix_"+name, func(c Config) (Engine, error) {
+			if err := supports("matrix_"+name, c, featLarge); err != nil {
+				return nil, err
+			}
+			m := NewMatrix(c)
+			m.neighbors = format(m.neighbors.(*sparse.CSR))
+			return m, nil
+		})
+	}
+}
+
+// matrixFormats converts the neighbours matrix to the other layouts, each
+// one registered as a matrix engine of its own.
+var matrixFormats = map[string]func(m *sparse.CSR) sparse.Matrix{
+	"coo": func(m *sparse.CSR) sparse.Matrix { return m.COO() },
+	"ell": func(m *sparse.CSR) sparse.Matrix { return m.ELL() },
+	"dia": func(m *sparse.CSR) sparse.Matrix { return m.DIA() },
+}
+
+// Matrix stores the padded world as a flat []int and counts neighbours with a
+// sparse matrix-vector product. The padding is as wide as the radius of the
+// neighbourhood, so Larger than Life rules only change the matrix.
+type Matrix struct {
+	cells     []int
+	alive     []int // live neighbours of every cell
+	neighbors sparse.Matrix
+	table     [2][]int
+	pad       int
+	w         int
+	h         int
+}
+
+// NewMatrix returns an empty matrix engine, with the neighbours matrix in CSR
+// format.
+func NewMatrix(c Config) *Matrix {
+	r := c.rule()
+	pad := 1
+	neighbors := neighborsMatrix(c.Width, c.Height, c.Topology)
+	if l := r.LtL(); l != nil {
+		pad = l.Radius
+		neighbors = stencilMatrix(c.Width, c.Height, pad, l.Neighborhood.Stencil(l.Radius, l.Middle), c.Topology)
+	}
+	n := (c.Width + 2*pad) * (c.Height + 2*pad)
+	return &Matrix{
+		cells:     make([]int, n),
+		alive:     make([]int, n),
+		neighbors: neighbors,
+		table:     ruleTable(r),
+		pad:       pad,
+		w:         c.Width,
+		h:         c.Height,
+	}
+}
+
+// neighborsMatrix creates the neighbors sparse matrix of a padded Nx x Ny
+// world.
+func neighborsMatrix(Nx, Ny int, topo Topology) *sparse.CSR {
+	return stencilMatrix(Nx, Ny, 1, Moore.Stencil(1, false), topo)
+}
+
+// stencilMatrix creates the sparse matrix that sums the cells at the
+// offsets of stencil for a Nx x Ny world padded with pad cells on each side.
+// The stencil must fit in the padding. Neighbours across a wrapped edge point
+// to the cell they stand for in topo, the others to the dead padding. The
+// rows of the padding are empty.
+func stencilMatrix(Nx, Ny, pad int, stencil []image.Point, topo Topology) *sparse.CSR {
+	W, H := Nx+2*pad, Ny+2*pad
+	inner := image.Rect(pad, pad, Nx+pad, Ny+pad)
+	return sparse.FromStencil(W, H, stencil, func(p, q image.Point) (int, bool) {
+		// Only add neighbors for cells in the active grid
+		if !p.In(inner) {
+			return 0, false
+		}
+		if x, y, ok := topo.wrap(q.X-pad, q.Y-pad, Nx, Ny); ok {
+			q = image.Pt(x+pad, y+pad)
+		}
+		return q.Y*W + q.X, true
+	})
+}
+
+// ruleTable compiles a rule into a table indexed by the current state and
+// the number of live neighbours, as used by the matrix engines.
+func ruleTable(r Rule) [2][]int {
+	birth, survive := countTables(&r)
+	t := [2][]int{make([]int, len(birth)), make([]int, len(survive))}
+	for n := range birth {
+		if birth[n] {
+			t[0][n] = 1
+		}
+		if survive[n] {
+			t[1][n] = 1
+		}
+	}
+	return t
+}
+
+// applyRules updates the cells of rows startRow to endRow of a Nx wide
+// world padded with pad cells in place from their live neighbour counts.
+// The padding is left dead, even for rules with B0.
+func applyRules(cells []int, alive []int, table *[2][]int, Nx, pad, startRow, endRow int) {
+	for i := startRow; i < endRow; i++ {
+		row := (i + pad) * (Nx + 2*pad)
+		for j := row + pad; j < row+pad+Nx; j++ {
+			cells[j] = table[cells[j]][alive[j]]
+		}
+	}
+}
+
+func (m *Matrix) Step() {
+	// matrix-vector multiplication
+	sparse.MulVec(m.neighbors, m.cells, m.alive)
+	// apply rules
+	applyRules(m.cells, m.alive, &m.table, m.w, m.pad, 0, m.h)
+}
+
+func (m *Matrix) StepN(its int) {
+	for i := 0; i < its; i++ {
+		m.Step()
+	}
+}
+
+func (m *Matrix) Get(x, y int) bool {
+	return getFlat(m.cells, m.w, m.h, m.pad, x, y)
+}
+
+func (m *Matrix) Set(x, y int, alive bool) {
+	setFlat(m.cells, m.w, m.h, m.pad, x, y, alive)
+}
+
+func (m *Matrix) Population() int {
+	return countFlat(m.cells)
+}
+
+func (m *Matrix) Bounds() image.Rectangle {
+	return image.Rect(0, 0, m.w, m.h)
+}
+
+// getFlat reads a cell of a Nx x Ny world padded with pad cells stored as a
+// flat []int.
+func getFlat(cells []int, Nx, Ny, pad, x, y int) bool {
+	if x < 0 || x >= Nx || y < 0 || y >= Ny {
+		return false
+	}
+	return cells[(y+pad)*(Nx+2*pad)+x+pad] == 1
+}
+
+// setFlat writes a cell of a Nx x Ny world padded with pad cells stored as a
+// flat []int.
+func setFlat(cells []int, Nx, Ny, pad, x, y int, alive bool) {
+	if x < 0 || x >= Nx || y < 0 || y >= Ny {
+		return
+	}
+	if alive {
+		cells[(y+pad)*(Nx+2*pad)+x+pad] = 1
+	} else {
+		cells[(y+pad)*(Nx+2*pad)+x+pad] = 0
+	}
+}
+
+func countFlat(cells []int) int {
+	n := 0
+	for _, c := range cells {
+		n += c
+	}
+	return n
+}
@@ -0,0 +1,95 @@
+package gogol
+
+import "image"
+
+func init() {
+	Register("naive", func(c Config) (Engine, error) {
+		if err := supports("naive", c, featHensel); err != nil {
+			return nil, err
+		}
+		return NewNaive(c), nil
+	})
+}
+
+// Naive stores the world as a [][]bool and bounds-checks every neighbour,
+// wrapping the ones outside the world around its topology.
+type Naive struct {
+	cells [][]bool
+	rule  Rule
+	topo  Topology
+	w     int
+	h     int
+}
+
+// NewNaive returns an empty naive engine.
+func NewNaive(c Config) *Naive {
+	return &Naive{cells: newCells(c.Width, c.Height), rule: c.rule(), topo: c.Topology, w: c.Width, h: c.Height}
+}
+
+func updateCell(cells [][]bool, i int, j int, Nx, Ny int, rule *Rule, topo Topology) bool {
+	// Count live neighbors, handling edges carefully
+	liveNeighbors := 0
+	var config, bit uint8 = 0, 1
+	for di := -1; di <= 1; di++ {
+		for dj := -1; dj <= 1; dj++ {
+			// Skip the cell itself
+			if di == 0 && dj == 0 {
+				continue
+			}
+			// Calculate neighbor coordinates
+			ni := i + di
+			nj := j + dj
+			// Check bounds, wrapping around the edges
+			if nj, ni, ok := topo.wrap(nj, ni, Nx, Ny); ok {
+				if cells[ni][nj] {
+					liveNeighbors++
+					config |= bit
+				}
+			}
+			bit <<= 1
+		}
+	}
+	// Apply the rule
+	return rule.NextConfig(cells[i][j], liveNeighbors, config)
+}
+
+func (n *Naive) Step() {
+	// Create a new grid for the next state
+	nextCells := newCells(n.w, n.h)
+	// Calculate the next state based on the current state
+	for i := range n.cells {
+		for j := range n.cells[i] {
+			nextCells[i][j] = updateCell(n.cells, i, j, n.w, n.h, &n.rule, n.topo)
+		}
+	}
+	// Replace the old grid with the new one
+	n.cells = nextCells
+}
+
+func (n *Naive) StepN(its int) {
+	for i := 0; i < its; i++ {
+		n.Step()
+	}
+}
+
+func (n *Naive) Get(x, y int) bool {
+	if x < 0 || x >= n.w || y < 0 || y >= n.h {
+		return false
+	}
+	return n.cells[y][x]
+}
+
+func (n *Naive) Set(x, y int, alive bool) {
+	if x < 0 || x >= n.w || y < 0 || y >= n.h {
+		return
+	}
+	n.cells[y][x] = alive
+}
+
+func (n *Naive) Population() int {
+	return countCells(n.cells)
+}
+
+func (n *Naive) Bounds() image.Rectangle {
+	return image.Rect(0, 0, n.w, n.h)
+}
@@ -0,0 +1,90 @@
+package gogol
+
+import "image"
+
+func init() {
+	Register("padded", func(c Config) (Engine, error) {
+		if err := supports("padded", c, featHensel); err != nil {
+			return nil, err
+		}
+		return NewPadded(c), nil
+	})
+}
+
+// Padded surrounds the world with a border of cells so neighbours can be
+// counted without bounds checks. The border is dead, or filled before each
+// generation with the cells across the edges for wrapped topologies.
+type Padded struct {
+	cells [][]bool
+	rule  Rule
+	topo  Topology
+	w     int
+	h     int
+}
+
+// NewPadded returns an empty padded engine.
+func NewPadded(c Config) *Padded {
+	return &Padded{cells: newCells(c.Width+2, c.Height+2), rule: c.rule(), topo: c.Topology, w: c.Width, h: c.Height}
+}
+
+func updateCellPadded(cells [][]bool, i int, j int, rule *Rule) bool {
+	liveNeighbors := 0
+	var config, bit uint8 = 0, 1
+	for di := -1; di <= 1; di++ {
+		for dj := -1; dj <= 1; dj++ {
+			if di == 0 && dj == 0 {
+				continue
+			}
+			// No bounds check needed, the border is filled
+			if cells[i+di][j+dj] {
+				liveNeighbors++
+				config |= bit
+			}
+			bit <<= 1
+		}
+	}
+	// Apply the rule
+	return rule.NextConfig(cells[i][j], liveNeighbors, config)
+}
+
+func (p *Padded) Step() {
+	fillHalo(p.cells, 1, p.topo)
+	// Create a new grid for the next state
+	nextCells := newCells(p.w+2, p.h+2)
+	// Calculate the next state based on the current state
+	for i := 1; i <= p.h; i++ {
+		for j := 1; j <= p.w; j++ {
+			nextCells[i][j] = updateCellPadded(p.cells, i, j, &p.rule)
+		}
+	}
+	// Replace the old grid with the new one
+	p.cells = nextCells
+}
+
+func (p *Padded) StepN(its int) {
+	for i := 0; i < its; i++ {
+		p.Step()
+	}
+}
+
+func (p *Padded) Get(x, y int) bool {
+	if x < 0 || x >= p.w || y < 0 || y >= p.h {
+		return false
+	}
+	return p.cells[y+1][x+1]
+}
+
+func (p *Padded) Set(x, y int, alive bool) {
+	if x < 0 || x >= p.w || y < 0 || y >= p.h {
+		return
+	}
+	p.cells[y+1][x+1] = alive
+}
+
+func (p *Padded) Population() int {
+	return countCells(p.cells)
+}
+
+func (p *Padded) Bounds() image.Rectangle {
+	return image.Rect(0, 0, p.w, p.h)
+}
@@ -0,0 +1,207 @@
+package gogol
+
+import (
+	"runtime"
+
+	"github.com/juansensio/gogol/sparse"
+)
+
+func init() {
+	Register("parallel", func(c Config) (Engine, error) {
+		if err := supports("parallel", c, featHensel); err != nil {
+			return nil, err
+		}
+		return NewParallel(c), nil
+	})
+	Register("parallel2", func(c Config) (Engine, error) {
+		if err := supports("parallel2", c, featHensel); err != nil {
+			return nil, err
+		}
+		return NewParallel2(c), nil
+	})
+	Register("parallel_matrix", func(c Config) (Engine, error) {
+		if err := supports("parallel_matrix", c, featLarge); err != nil {
+			return nil, err
+		}
+		return NewMatrixParallel(c), nil
+	})
+	for name, format := range matrixFormats {
+		Register("parallel_matrix_"+name, func(c Config) (Engine, error) {
+			if err := supports("parallel_matrix_"+name, c, featLarge); err != nil {
+				return nil, err
+			}
+			m := NewMatrixParallel(c)
+			m.neighbors = format(m.neighbors.(*sparse.CSR))
+			return m, nil
+		})
+	}
+}
+
+// Parallel splits the rows between a pool of workers, each one computing
+// its band of the next generation in a private grid it owns and then copying
+// it into the shared next generation.
+type Parallel struct {
+	Naive
+	next  [][]bool
+	parts [][][]bool // private grid of each worker
+	pool  *pool
+}
+
+// NewParallel returns an empty parallel engine. Close stops its workers,
+// which are otherwise stopped when the engine is garbage collected.
+func NewParallel(c Config) *Parallel {
+	p := &Parallel{Naive: *NewNaive(c), next: newCells(c.Width, c.Height), pool: newPool(max(c.Workers, 1))}
+	for w := 0; w < p.pool.workers; w++ {
+		start, end := p.pool.rows(w, p.h)
+		p.parts = append(p.parts, newCells(p.w, end-start))
+	}
+	runtime.SetFinalizer(p, func(p *Parallel) { p.pool.close() })
+	return p
+}
+
+func updateCellParallel(cells [][]bool, part [][]bool, startRow, endRow int, Nx, Ny int, rule *Rule, topo Topology) {
+	// Process only the assigned rows
+	for i := startRow; i < endRow; i++ {
+		localI := i - startRow // Local index for our section
+		for j := range cells[i] {
+			part[localI][j] = updateCell(cells, i, j, Nx, Ny, rule, topo)
+		}
+	}
+}
+
+func (p *Parallel) Step() {
+	p.StepN(1)
+}
+
+func (p *Parallel) Workers() int {
+	return p.pool.workers
+}
+
+// Close stops the workers. The engine must not be stepped afterwards.
+func (p *Parallel) Close() error {
+	p.pool.close()
+	return nil
+}
+
+// StepN runs its generations in a single task of the pool, the workers
+// meeting at a barrier after each phase of a generation.
+func (p *Parallel) StepN(its int) {
+	p.pool.run(func(w int) {
+		start, end := p.pool.rows(w, p.h)
+		part := p.parts[w]
+		for i := 0; i < its; i++ {
+			updateCellParallel(p.cells, part, start, end, p.w, p.h, &p.rule, p.topo)
+			// Copy the partial result to the combined grid
+			for r := range part {
+				copy(p.next[start+r], part[r])
+			}
+			p.pool.sync()
+			if w == 0 {
+				p.cells, p.next = p.next, p.cells
+			}
+			p.pool.sync()
+		}
+	})
+}
+
+// Parallel2 splits the rows between a pool of workers that write directly
+// into a shared next generation grid.
+type Parallel2 struct {
+	Naive
+	next [][]bool
+	pool *pool
+}
+
+// NewParallel2 returns an empty parallel2 engine. Close stops its workers,
+// which are otherwise stopped when the engine is garbage collected.
+func NewParallel2(c Config) *Parallel2 {
+	p := &Parallel2{Naive: *NewNaive(c), next: newCells(c.Width, c.Height), pool: newPool(max(c.Workers, 1))}
+	runtime.SetFinalizer(p, func(p *Parallel2) { p.pool.close() })
+	return p
+}
+
+func updateCellParallel2(cells [][]bool, nextCells [][]bool, startRow, endRow int, Nx, Ny int, rule *Rule, topo Topology) {
+	// Process only the assigned rows
+	for i := startRow; i < endRow; i++ {
+		for j := range cells[i] {
+			nextCells[i][j] = updateCell(cells, i, j, Nx, Ny, rule, topo)
+		}
+	}
+}
+
+func (p *Parallel2) Step() {
+	p.StepN(1)
+}
+
+func (p *Parallel2) Workers() int {
+	return p.pool.workers
+}
+
+// Close stops the workers. The engine must not be stepped afterwards.
+func (p *Parallel2) Close() error {
+	p.pool.close()
+	return nil
+}
+
+// StepN runs its generations in a single task of the pool, the workers
+// meeting at a barrier before and after swapping the grids.
+func (p *Parallel2) StepN(its int) {
+	p.pool.run(func(w int) {
+		start, end := p.pool.rows(w, p.h)
+		for i := 0; i < its; i++ {
+			updateCellParallel2(p.cells, p.next, start, end, p.w, p.h, &p.rule, p.topo)
+			p.pool.sync()
+			if w == 0 {
+				p.cells, p.next = p.next, p.cells
+			}
+			p.pool.sync()
+		}
+	})
+}
+
+// MatrixParallel is the matrix engine with the sparse product and the rules
+// split between a pool of workers.
+type MatrixParallel struct {
+	Matrix
+	pool *pool
+}
+
+// NewMatrixParallel returns an empty parallel matrix engine. Close stops its
+// workers, which are otherwise stopped when the engine is garbage collected.
+func NewMatrixParallel(c Config) *MatrixParallel {
+	m := &MatrixParallel{Matrix: *NewMatrix(c), pool: newPool(max(c.Workers, 1))}
+	runtime.SetFinalizer(m, func(m *MatrixParallel) { m.pool.close() })
+	return m
+}
+
+func (m *MatrixParallel) Step() {
+	m.StepN(1)
+}
+
+func (m *MatrixParallel) Workers() int {
+	return m.pool.workers
+}
+
+// Close stops the workers. The engine must not be stepped afterwards.
+func (m *MatrixParallel) Close() error {
+	m.pool.close()
+	return nil
+}
+
+// StepN runs its generations in a single task of the pool: every worker
+// multiplies its share of the matrix rows and, after a barrier, applies the
+// rules to its share of the world rows.
+func (m *MatrixParallel) StepN(its int) {
+	m.pool.run(func(w int) {
+		start, end := m.pool.rows(w, len(m.cells))
+		startRow, endRow := m.pool.rows(w, m.h)
+		for i := 0; i < its; i++ {
+			// matrix-vector multiplication (parallel version)
+			m.neighbors.MulVecRows(m.cells, m.alive, start, end)
+			m.pool.sync()
+			// apply rules (parallel version)
+			applyRules(m.cells, m.alive, &m.table, m.w, m.pad, startRow, endRow)
+			m.pool.sync()
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package gogol
+
+import (
+	"fmt"
+	"image"
+)
+
+func init() {
+	Register("sparse", func(c Config) (Engine, error) {
+		if err := supports("sparse", c, featHensel); err != nil {
+			return nil, err
+		}
+		if r := c.rule(); r.Birth[0] {
+			return nil, fmt.Errorf("gogol: engine sparse cannot run rule %v, B0 fills the infinite plane", r)
+		}
+		if c.Topology != "" && c.Topology != Plane {
+			return nil, fmt.Errorf("gogol: engine sparse only runs on the plane, got %s", c.Topology)
+		}
+		return NewSparse(c), nil
+	})
+}
+
+// Sparse is an unbounded world that only stores its live cells in a set,
+// so patterns can travel and grow forever. Its bounds start as the
+// configured size and grow to hold every live cell.
+type Sparse struct {
+	live   map[image.Point]struct{}
+	counts map[image.Point]int // live neighbours of the cells next to live ones
+	rule   Rule
+	view   image.Rectangle // configured size
+	box    image.Rectangle // holds every live cell
+}
+
+// NewSparse returns an empty sparse engine.
+func NewSparse(c Config) *Sparse {
+	return &Sparse{
+		live:   make(map[image.Point]struct{}),
+		counts: make(map[image.Point]int),
+		rule:   c.rule(),
+		view:   image.Rect(0, 0, c.Width, c.Height),
+	}
+}
+
+// config returns the configuration of the live neighbours of p, for
+// non-totalistic rules.
+func (s *Sparse) config(p image.Point) uint8 {
+	var config, bit uint8 = 0, 1
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if dx == 0 && dy == 0 {
+				continue
+			}
+			if _, ok := s.live[p.Add(image.Pt(dx, dy))]; ok {
+				config |= bit
+			}
+			bit <<= 1
+		}
+	}
+	return config
+}
+
+func (s *Sparse) Step() {
+	// Count live neighbors of every cell next to a live cell
+	clear(s.counts)
+	for p := range s.live {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if dx != 0 || dy != 0 {
+					s.counts[p.Add(image.Pt(dx, dy))]++
+				}
+			}
+		}
+	}
+	// Apply the rule, live cells without neighbours are not in counts
+	next := make(map[image.Point]struct{}, len(s.live))
+	s.box = image.Rectangle{}
+	add := func(p image.Point) {
+		next[p] = struct{}{}
+		s.box = s.box.Union(image.Rectangle{p, p.Add(image.Pt(1, 1))})
+	}
+	for p, n := range s.counts {
+		_, alive := s.live[p]
+		var config uint8
+		if !s.rule.Totalistic() {
+			config = s.config(p)
+		}
+		if s.rule.NextConfig(alive, n, config) {
+			add(p)
+		}
+	}
+	if s.rule.Survive[0] {
+		for p := range s.live {
+			if _, ok := s.counts[p]; !ok {
+				add(p)
+			}
+		}
+	}
+	s.live = next
+}
+
+func (s *Sparse) StepN(its int) {
+	for i := 0; i < its; i++ {
+		s.Step()
+	}
+}
+
+func (s *Sparse) Get(x, y int) bool {
+	_, ok := s.live[image.Pt(x, y)]
+	return ok
+}
+
+// Set sets the state of a cell anywhere in the plane.
+func (s *Sparse) Set(x, y int, alive bool) {
+	p := image.Pt(x, y)
+	if !alive {
+		delete(s.live, p)
+		return
+	}
+	s.live[p] = struct{}{}
+	s.box = s.box.Union(image.Rectangle{p, p.Add(image.Pt(1, 1))})
+}
+
+func (s *Sparse) Population() int {
+	return len(s.live)
+}
+
+// Bounds returns the configured size grown to hold every live cell.
+func (s *Sparse) Bounds() image.Rectangle {
+	return s.view.Union(s.box)
+}
+
+// Unbounded marks the engine as an infinite plane.
+func (s *Sparse) Unbounded() {}
+
+// LoadQuadtree copies every live cell of q, with the top-left corner of
+// its bounds at (x, y).
+func (s *Sparse) LoadQuadtree(q *Quadtree, x, y int) {
+	r := q.Bounds()
+	q.Each(r, func(cx, cy int) {
+		s.Set(cx-r.Min.X+x, cy-r.Min.Y+y, true)
+	})
+}
@@ -0,0 +1,49 @@
+package gogol
+
+import (
+	"image"
+	"testing"
+)
+
+// checkGlider runs a glider for gens generations, a multiple of 4, on the
+// unbounded engine name, and checks it moved gens/4 cells down and right,
+// far beyond the size the engine was created with.
+func checkGlider(t *testing.T, name string, gens int) {
+	t.Helper()
+	e, err := New(name, Config{Width: 10, Height: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := e.(Unbounded); !ok {
+		t.Fatalf("%s is not unbounded", name)
+	}
+	// Start above and left of the origin
+	LoadAt(e, glider(), -5, -5)
+	e.StepN(gens)
+	d := gens / 4
+	want := glider()
+	if got := e.Population(); got != want.Population() {
+		t.Fatalf("got %d cells, want %d", got, want.Population())
+	}
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 3; x++ {
+			if e.Get(x-5+d, y-5+d) != want.Get(x, y) {
+				t.Fatalf("glider not found at (%d, %d)", d-5, d-5)
+			}
+		}
+	}
+	if r := e.Bounds(); !image.Pt(d-5+2, d-5+2).In(r) || !image.Pt(0, 0).In(r) {
+		t.Errorf("bounds %v do not hold the glider and the initial size", r)
+	}
+}
+
+func TestSparseGlider(t *testing.T) {
+	checkGlider(t, "sparse", 400)
+}
+
+func TestSparseB0(t *testing.T) {
+	r := MustParseRule("B03/S23")
+	if _, err := New("sparse", Config{Width: 4, Height: 4, Rule: &r}); err == nil {
+		t.Error("sparse runs B0 rules")
+	}
+}
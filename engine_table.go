@@ -0,0 +1,170 @@
+package gogol
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+func init() {
+	Register("table", func(c Config) (Engine, error) {
+		if r := c.rule(); r.Table() == nil {
+			return nil, fmt.Errorf("gogol: engine table needs a rule table, got %v", r)
+		}
+		return NewTableEngine(c), nil
+	})
+}
+
+// TableEngine is a padded world of multi-state cells that runs a RuleTable.
+// The next state of every neighbourhood is looked up in the table once and
+// cached.
+type TableEngine struct {
+	cells [][]uint8
+	next  [][]uint8
+	table *RuleTable
+	topo  Topology
+	order [8]int             // position in Golly's order of the neighbours in reading order
+	cache []uint8            // next state by neighbourhood, 0xff if not computed yet
+	seen  map[[9]uint8]uint8 // cache of the tables with too many neighbourhoods
+	w     int
+	h     int
+}
+
+// denseCache is the largest number of neighbourhoods cached in a slice.
+const denseCache = 1 << 22
+
+// NewTableEngine returns an empty engine for the rule table of c.
+func NewTableEngine(c Config) *TableEngine {
+	r := c.rule()
+	t := r.Table()
+	e := &TableEngine{
+		cells: newStates(c.Width+2, c.Height+2),
+		next:  newStates(c.Width+2, c.Height+2),
+		table: t,
+		topo:  c.Topology,
+		w:     c.Width,
+		h:     c.Height,
+	}
+	// NW, N, NE, W, E, SW, S, SE in Golly's order, 0 if not a neighbour
+	if t.Neighborhood == "vonNeumann" {
+		e.order = [8]int{0, 1, 0, 4, 2, 0, 3, 0}
+	} else {
+		e.order = [8]int{8, 1, 2, 7, 3, 6, 5, 4}
+	}
+	n := 1
+	for i := 0; i < t.size() && n <= denseCache; i++ {
+		n *= t.States
+	}
+	if n <= denseCache {
+		e.cache = make([]uint8, n)
+		for i := range e.cache {
+			e.cache[i] = 0xff
+		}
+	} else {
+		e.seen = make(map[[9]uint8]uint8)
+	}
+	return e
+}
+
+func (e *TableEngine) updateCell(i int, j int) uint8 {
+	// Collect the neighbours in Golly's order
+	var nb [9]uint8
+	nb[0] = e.cells[i][j]
+	k := 0
+	for di := -1; di <= 1; di++ {
+		for dj := -1; dj <= 1; dj++ {
+			if di == 0 && dj == 0 {
+				continue
+			}
+			if o := e.order[k]; o > 0 {
+				nb[o] = e.cells[i+di][j+dj]
+			}
+			k++
+		}
+	}
+	// Look up the table
+	if e.cache == nil {
+		s, ok := e.seen[nb]
+		if !ok {
+			s = e.table.Next(nb[:e.table.size()])
+			e.seen[nb] = s
+		}
+		return s
+	}
+	idx := 0
+	for n := e.table.size() - 1; n >= 0; n-- {
+		idx = idx*e.table.States + int(nb[n])
+	}
+	if e.cache[idx] == 0xff {
+		e.cache[idx] = e.table.Next(nb[:e.table.size()])
+	}
+	return e.cache[idx]
+}
+
+func (e *TableEngine) Step() {
+	fillHalo(e.cells, 1, e.topo)
+	for i := 1; i <= e.h; i++ {
+		for j := 1; j <= e.w; j++ {
+			e.next[i][j] = e.updateCell(i, j)
+		}
+	}
+	e.cells, e.next = e.next, e.cells
+}
+
+func (e *TableEngine) StepN(its int) {
+	for i := 0; i < its; i++ {
+		e.Step()
+	}
+}
+
+func (e *TableEngine) Get(x, y int) bool {
+	return e.State(x, y) != 0
+}
+
+func (e *TableEngine) Set(x, y int, alive bool) {
+	var s uint8
+	if alive {
+		s = 1
+	}
+	e.SetState(x, y, s)
+}
+
+func (e *TableEngine) StateCount() int {
+	return e.table.States
+}
+
+func (e *TableEngine) State(x, y int) uint8 {
+	if x < 0 || x >= e.w || y < 0 || y >= e.h {
+		return 0
+	}
+	return e.cells[y+1][x+1]
+}
+
+func (e *TableEngine) SetState(x, y int, s uint8) {
+	if x < 0 || x >= e.w || y < 0 || y >= e.h || int(s) >= e.table.States {
+		return
+	}
+	e.cells[y+1][x+1] = s
+}
+
+// Colors returns the colours of the @COLORS section of the table.
+func (e *TableEngine) Colors() color.Palette {
+	return e.table.Colors
+}
+
+// Population returns the number of cells that are not in state 0.
+func (e *TableEngine) Population() int {
+	n := 0
+	for _, row := range e.cells[1 : e.h+1] {
+		for _, s := range row[1 : e.w+1] {
+			if s != 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func (e *TableEngine) Bounds() image.Rectangle {
+	return image.Rect(0, 0, e.w, e.h)
+}
@@ -0,0 +1,250 @@
+package gogol
+
+import "image"
+
+func init() {
+	Register("tiled", func(c Config) (Engine, error) {
+		if err := supports("tiled", c, featHensel); err != nil {
+			return nil, err
+		}
+		return NewTiled(c), nil
+	})
+}
+
+// TileSize is the width and height in cells of the tiles of the tiled
+// engine.
+const TileSize = 8
+
+// TileStats counts the tiles of a tiled world.
+type TileStats struct {
+	Tiles    int // tiles in the world
+	Active   int // tiles recomputed in the last generation
+	Computed int64
+	Skipped  int64 // tiles skipped since the start
+}
+
+// SkippedFraction returns the fraction of the tiles skipped since the start.
+func (s TileStats) SkippedFraction() float64 {
+	if s.Computed+s.Skipped == 0 {
+		return 0
+	}
+	return float64(s.Skipped) / float64(s.Computed+s.Skipped)
+}
+
+// Tiled is a padded world split into tiles of TileSize x TileSize cells
+// where only the tiles that changed in the last generation are recomputed,
+// with the neighbours that touch the cells that changed, so settled soups
+// only cost their oscillators.
+// Skipped tiles are the same in both buffers: they have not changed for two
+// generations.
+type Tiled struct {
+	cells   [][]bool
+	next    [][]bool
+	changed []uint16 // parts of the tiles that changed in the last generation
+	active  []bool   // tiles to recompute in the next generation
+	tw      int      // tiles per row
+	th      int      // rows of tiles
+	stats   TileStats
+	rule    Rule
+	topo    Topology
+	w       int
+	h       int
+}
+
+// NewTiled returns an empty tiled engine.
+func NewTiled(c Config) *Tiled {
+	tw, th := (c.Width+TileSize-1)/TileSize, (c.Height+TileSize-1)/TileSize
+	t := &Tiled{
+		cells:   newCells(c.Width+2, c.Height+2),
+		next:    newCells(c.Width+2, c.Height+2),
+		changed: make([]uint16, tw*th),
+		active:  make([]bool, tw*th),
+		tw:      tw,
+		th:      th,
+		rule:    c.rule(),
+		topo:    c.Topology,
+		w:       c.Width,
+		h:       c.Height,
+	}
+	t.stats.Tiles = tw * th
+	for i := range t.active {
+		t.active[i] = true
+	}
+	return t
+}
+
+// Flags telling which parts of a tile changed, and so which neighbours must
+// be recomputed.
+const (
+	changedTile uint16 = 1 << iota
+	changedN
+	changedS
+	changedW
+	changedE
+	changedNW
+	changedNE
+	changedSW
+	changedSE
+	changedAll = 1<<iota - 1
+)
+
+// updateTile computes the next generation of a tile and returns the parts of
+// it that changed.
+func (t *Tiled) updateTile(tx, ty int) uint16 {
+	var changed uint16
+	top, left := 1+ty*TileSize, 1+tx*TileSize
+	bottom, right := min((ty+1)*TileSize, t.h), min((tx+1)*TileSize, t.w)
+	for i := top; i <= bottom; i++ {
+		for j := left; j <= right; j++ {
+			alive := updateCellPadded(t.cells, i, j, &t.rule)
+			t.next[i][j] = alive
+			if alive == t.cells[i][j] {
+				continue
+			}
+			changed |= changedTile
+			n, s, w, e := i == top, i == bottom, j == left, j == right
+			if n {
+				changed |= changedN
+			}
+			if s {
+				changed |= changedS
+			}
+			if w {
+				changed |= changedW
+			}
+			if e {
+				changed |= changedE
+			}
+			if n && w {
+				changed |= changedNW
+			}
+			if n && e {
+				changed |= changedNE
+			}
+			if s && w {
+				changed |= changedSW
+			}
+			if s && e {
+				changed |= changedSE
+			}
+		}
+	}
+	return changed
+}
+
+// activate marks as active the tiles that changed and the neighbours next to
+// the cells that changed.
+func (t *Tiled) activate() {
+	clear(t.active)
+	for ty := 0; ty < t.th; ty++ {
+		for tx := 0; tx < t.tw; tx++ {
+			if c := t.changed[ty*t.tw+tx]; c != 0 {
+				t.wake(tx, ty, c)
+			}
+		}
+	}
+}
+
+// tileNeighbors are the offsets of the neighbours of a tile woken by each
+// changed flag.
+var tileNeighbors = [...]struct {
+	flag   uint16
+	dx, dy int
+}{
+	{changedTile, 0, 0},
+	{changedN, 0, -1}, {changedS, 0, 1}, {changedW, -1, 0}, {changedE, 1, 0},
+	{changedNW, -1, -1}, {changedNE, 1, -1}, {changedSW, -1, 1}, {changedSE, 1, 1},
+}
+
+// wake marks as active a tile and the neighbours selected by the changed
+// flags. On wrapped topologies a change on an edge of the world wakes the
+// whole border, which holds every tile across the edges.
+func (t *Tiled) wake(tx, ty int, changed uint16) {
+	outside := false
+	for _, n := range tileNeighbors {
+		if changed&n.flag == 0 {
+			continue
+		}
+		x, y := tx+n.dx, ty+n.dy
+		if x < 0 || x >= t.tw || y < 0 || y >= t.th {
+			outside = true
+			continue
+		}
+		t.active[y*t.tw+x] = true
+	}
+	if !outside || t.topo == Plane || t.topo == "" {
+		return
+	}
+	for x := 0; x < t.tw; x++ {
+		t.active[x] = true
+		t.active[(t.th-1)*t.tw+x] = true
+	}
+	for y := 0; y < t.th; y++ {
+		t.active[y*t.tw] = true
+		t.active[y*t.tw+t.tw-1] = true
+	}
+}
+
+func (t *Tiled) Step() {
+	fillHalo(t.cells, 1, t.topo)
+	t.stats.Active = 0
+	for ty := 0; ty < t.th; ty++ {
+		for tx := 0; tx < t.tw; tx++ {
+			k := ty*t.tw + tx
+			t.changed[k] = 0
+			if !t.active[k] {
+				t.stats.Skipped++
+				continue
+			}
+			t.changed[k] = t.updateTile(tx, ty)
+			t.stats.Active++
+			t.stats.Computed++
+		}
+	}
+	t.cells, t.next = t.next, t.cells
+	t.activate()
+}
+
+func (t *Tiled) StepN(its int) {
+	for i := 0; i < its; i++ {
+		t.Step()
+	}
+}
+
+// Stats returns the tile counts.
+func (t *Tiled) Stats() TileStats {
+	return t.stats
+}
+
+func (t *Tiled) Get(x, y int) bool {
+	if x < 0 || x >= t.w || y < 0 || y >= t.h {
+		return false
+	}
+	return t.cells[y+1][x+1]
+}
+
+// Set sets the state of a cell and wakes its tile and the neighbouring ones.
+func (t *Tiled) Set(x, y int, alive bool) {
+	if x < 0 || x >= t.w || y < 0 || y >= t.h || t.cells[y+1][x+1] == alive {
+		return
+	}
+	t.cells[y+1][x+1] = alive
+	t.changed[(y/TileSize)*t.tw+x/TileSize] = changedAll
+	t.wake(x/TileSize, y/TileSize, changedAll)
+}
+
+func (t *Tiled) Population() int {
+	n := 0
+	for _, row := range t.cells[1 : t.h+1] {
+		for _, alive := range row[1 : t.w+1] {
+			if alive {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func (t *Tiled) Bounds() image.Rectangle {
+	return image.Rect(0, 0, t.w, t.h)
+}
@@ -0,0 +1,41 @@
+package gogol
+
+import "testing"
+
+func TestTiledSkipsSettledTiles(t *testing.T) {
+	// A blinker in the middle of a tile keeps only that tile active
+	e := NewTiled(Config{Width: 64, Height: 64})
+	for x := 19; x <= 21; x++ {
+		e.Set(x, 20, true)
+	}
+	e.StepN(3)
+	s := e.Stats()
+	if s.Active != 1 {
+		t.Errorf("%d active tiles, want 1", s.Active)
+	}
+	if e.Population() != 3 || !e.Get(20, 19) || !e.Get(20, 21) {
+		t.Error("the blinker did not turn")
+	}
+	e.StepN(100)
+	if f := e.Stats().SkippedFraction(); f < 0.95 {
+		t.Errorf("skipped %.2f of the tiles, want most of them", f)
+	}
+}
+
+func TestTiledSetWakes(t *testing.T) {
+	// Cells set in a settled world, across the edges of tiles, wake them
+	for _, topo := range topologies {
+		c := Config{Width: 40, Height: 30, Topology: topo}
+		e, want := NewTiled(c), NewNaive(c)
+		e.StepN(5)
+		LoadAt(e, glider(), 6, 6)
+		LoadAt(want, glider(), 6, 6)
+		for gen := 1; gen <= 120; gen++ {
+			e.Step()
+			want.Step()
+			if !sameWorld(e, want) {
+				t.Fatalf("%s: differs from naive at generation %d", topo, gen)
+			}
+		}
+	}
+}
@@ -0,0 +1,232 @@
+// Package fft computes fast Fourier transforms of sequences and grids whose
+// sizes are powers of two, and convolves real grids with arbitrary kernels
+// through them.
+package fft
+
+import (
+	"math"
+	"math/bits"
+)
+
+// Size returns the smallest power of two not less than n.
+func Size(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	return 1 << bits.Len(uint(n-1))
+}
+
+// plan holds what transforms of length n share: the bit reversal
+// permutation and the twiddle factors.
+type plan struct {
+	n        int
+	rev      []int
+	twiddle  [2][]complex128 // exp(∓2πik/n) for k < n/2, forward and inverse
+	inverse1 complex128      // 1/n
+}
+
+func newPlan(n int) *plan {
+	if n <= 0 || n&(n-1) != 0 {
+		panic("fft: length is not a power of two")
+	}
+	p := &plan{n: n, rev: make([]int, n), inverse1: complex(1/float64(n), 0)}
+	shift := bits.UintSize - bits.Len(uint(n-1))
+	for i := range p.rev {
+		p.rev[i] = int(bits.Reverse(uint(i)) >> shift)
+	}
+	p.twiddle = [2][]complex128{make([]complex128, n/2), make([]complex128, n/2)}
+	for k := 0; k < n/2; k++ {
+		s, c := math.Sincos(-2 * math.Pi * float64(k) / float64(n))
+		p.twiddle[0][k] = complex(c, s)
+		p.twiddle[1][k] = complex(c, -s)
+	}
+	return p
+}
+
+// transform replaces x by its transform, the inverse one divided by n.
+func (p *plan) transform(x []complex128, inverse bool) {
+	n := p.n
+	for i, j := range p.rev {
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	twiddle := p.twiddle[0]
+	if inverse {
+		twiddle = p.twiddle[1]
+	}
+	// Butterflies of growing size
+	for size := 2; size <= n; size <<= 1 {
+		half, step := size/2, n/size
+		for start := 0; start < n; start += size {
+			a, b := x[start:start+half], x[start+half:start+size]
+			for k := range a {
+				t := twiddle[k*step] * b[k]
+				a[k], b[k] = a[k]+t, a[k]-t
+			}
+		}
+	}
+	if inverse {
+		for i := range x {
+			x[i] *= p.inverse1
+		}
+	}
+}
+
+// FFT replaces x by its discrete Fourier transform. The length of x must be
+// a power of two.
+func FFT(x []complex128) {
+	newPlan(len(x)).transform(x, false)
+}
+
+// IFFT replaces x by its inverse discrete Fourier transform. The length of x
+// must be a power of two.
+func IFFT(x []complex128) {
+	newPlan(len(x)).transform(x, true)
+}
+
+// Tap is the weight of a kernel at an offset from the cell.
+type Tap struct {
+	X, Y   int
+	Weight float64
+}
+
+// Convolution sums, for every cell of a w x h grid, the cells at the offsets
+// of a kernel times their weights, wrapping around the edges. Its cost does
+// not depend on the size of the kernel.
+//
+// The grids being real, only the w/2+1 first columns of their transforms are
+// kept, the others being their complex conjugates, and the rows are
+// transformed two at a time as the real and imaginary parts of a single
+// complex row.
+type Convolution struct {
+	w, h     int
+	rows     *plan
+	cols     *plan
+	spectrum []complex128 // transform of the kernel, h rows of w/2+1
+	buf      []complex128 // transform of the grid, h rows of w/2+1
+	row      []complex128
+	col      []complex128
+}
+
+// NewConvolution returns the convolution of w x h grids with kernel, w and h
+// powers of two.
+func NewConvolution(w, h int, kernel []Tap) *Convolution {
+	c := &Convolution{
+		w:        w,
+		h:        h,
+		rows:     newPlan(w),
+		cols:     newPlan(h),
+		spectrum: make([]complex128, h*(w/2+1)),
+		buf:      make([]complex128, h*(w/2+1)),
+		row:      make([]complex128, w),
+		col:      make([]complex128, h),
+	}
+	// The weight of offset d goes to -d, so the product of the transforms
+	// sums the cells at +d
+	grid := make([]float64, w*h)
+	for _, t := range kernel {
+		grid[mod(-t.Y, h)*w+mod(-t.X, w)] += t.Weight
+	}
+	c.forwardRows(grid, c.spectrum)
+	for j := 0; j <= w/2; j++ {
+		c.column(c.spectrum, j)
+		c.cols.transform(c.col, false)
+		c.setColumn(c.spectrum, j)
+	}
+	return c
+}
+
+func mod(a, n int) int {
+	return (a%n + n) % n
+}
+
+// Apply sets out to the convolution of in. in holds the first rows of a w x h
+// grid stored row by row, the others being zero, and out receives the first
+// rows of the result, as many as it holds.
+func (c *Convolution) Apply(in, out []float64) {
+	c.forwardRows(in, c.buf)
+	for j := 0; j <= c.w/2; j++ {
+		c.column(c.buf, j)
+		c.cols.transform(c.col, false)
+		for i := range c.col {
+			c.col[i] *= c.spectrum[i*(c.w/2+1)+j]
+		}
+		c.cols.transform(c.col, true)
+		c.setColumn(c.buf, j)
+	}
+	c.inverseRows(c.buf, out)
+}
+
+// forwardRows transforms the rows of in into the first columns of spectrum.
+func (c *Convolution) forwardRows(in []float64, spectrum []complex128) {
+	w, half := c.w, c.w/2+1
+	rows := len(in) / w
+	clear(spectrum[rows*half:])
+	for i := 0; i < rows; i += 2 {
+		for k := range c.row {
+			var b float64
+			if i+1 < rows {
+				b = in[(i+1)*w+k]
+			}
+			c.row[k] = complex(in[i*w+k], b)
+		}
+		c.rows.transform(c.row, false)
+		// Split the transforms of the real and imaginary parts
+		for k := 0; k < half; k++ {
+			z, zc := c.row[k], conj(c.row[(w-k)%w])
+			spectrum[i*half+k] = (z + zc) / 2
+			if i+1 < rows {
+				spectrum[(i+1)*half+k] = (z - zc) / 2i
+			}
+		}
+	}
+}
+
+// inverseRows transforms back the first columns in spectrum into the rows of
+// out.
+func (c *Convolution) inverseRows(spectrum []complex128, out []float64) {
+	w, half := c.w, c.w/2+1
+	rows := len(out) / w
+	for i := 0; i < rows; i += 2 {
+		// The transform of a + ib from the ones of a and b
+		for k := range c.row {
+			var a, b complex128
+			if k < half {
+				a = spectrum[i*half+k]
+				if i+1 < c.h {
+					b = spectrum[(i+1)*half+k]
+				}
+			} else {
+				a = conj(spectrum[i*half+w-k])
+				if i+1 < c.h {
+					b = conj(spectrum[(i+1)*half+w-k])
+				}
+			}
+			c.row[k] = a + 1i*b
+		}
+		c.rows.transform(c.row, true)
+		for k, z := range c.row {
+			out[i*w+k] = real(z)
+			if i+1 < rows {
+				out[(i+1)*w+k] = imag(z)
+			}
+		}
+	}
+}
+
+func (c *Convolution) column(spectrum []complex128, j int) {
+	for i := range c.col {
+		c.col[i] = spectrum[i*(c.w/2+1)+j]
+	}
+}
+
+func (c *Convolution) setColumn(spectrum []complex128, j int) {
+	for i, v := range c.col {
+		spectrum[i*(c.w/2+1)+j] = v
+	}
+}
+
+func conj(z complex128) complex128 {
+	return complex(real(z), -imag(z))
+}
@@ -0,0 +1,112 @@
+package fft
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestSize(t *testing.T) {
+	tests := map[int]int{-3: 1, 0: 1, 1: 1, 2: 2, 3: 4, 4: 4, 5: 8, 1000: 1024, 1024: 1024, 1025: 2048}
+	for n, want := range tests {
+		if got := Size(n); got != want {
+			t.Errorf("Size(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func randomComplex(n int, r *rand.Rand) []complex128 {
+	x := make([]complex128, n)
+	for i := range x {
+		x[i] = complex(r.Float64()*2-1, r.Float64()*2-1)
+	}
+	return x
+}
+
+// dft is the transform by its definition.
+func dft(x []complex128) []complex128 {
+	n := len(x)
+	out := make([]complex128, n)
+	for k := range out {
+		for j, v := range x {
+			out[k] += v * cmplx.Exp(complex(0, -2*math.Pi*float64(j*k)/float64(n)))
+		}
+	}
+	return out
+}
+
+func near(a, b []complex128) bool {
+	for i := range a {
+		if cmplx.Abs(a[i]-b[i]) > 1e-9 {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
+
+func TestFFT(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for n := 1; n <= 256; n *= 2 {
+		x := randomComplex(n, r)
+		y := append([]complex128(nil), x...)
+		FFT(y)
+		if !near(y, dft(x)) {
+			t.Errorf("length %d: FFT differs from the DFT", n)
+		}
+		IFFT(y)
+		if !near(y, x) {
+			t.Errorf("length %d: IFFT does not undo FFT", n)
+		}
+	}
+}
+
+func TestFFTLength(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic for a length of 6")
+		}
+	}()
+	FFT(make([]complex128, 6))
+}
+
+// direct convolves by summing the kernel at every cell.
+func direct(w, h int, kernel []Tap, in []float64) []float64 {
+	out := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			for _, t := range kernel {
+				i := mod(y+t.Y, h)*w + mod(x+t.X, w)
+				if i < len(in) {
+					out[y*w+x] += t.Weight * in[i]
+				}
+			}
+		}
+	}
+	return out
+}
+
+func TestConvolution(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	// an asymmetric kernel with offsets larger than the grid, which wrap
+	kernel := []Tap{{0, 0, 0.5}, {1, 0, 1}, {-2, 1, 2}, {3, -3, -1}, {0, 5, 0.25}, {-9, 0, 3}}
+	for _, size := range [][2]int{{1, 1}, {1, 8}, {8, 1}, {2, 2}, {4, 8}, {16, 4}, {32, 32}} {
+		w, h := size[0], size[1]
+		c := NewConvolution(w, h, kernel)
+		// all the rows, and fewer rows in and out, even and odd
+		for _, rows := range [][2]int{{h, h}, {h - 1, h}, {h / 2, h - 1}, {h, 1}} {
+			in := make([]float64, max(rows[0], 0)*w)
+			for i := range in {
+				in[i] = float64(r.IntN(2))
+			}
+			out := make([]float64, max(rows[1], 0)*w)
+			c.Apply(in, out)
+			want := direct(w, h, kernel, in)
+			for i := range out {
+				if math.Abs(out[i]-want[i]) > 1e-9 {
+					t.Fatalf("%dx%d, %d rows in, %d out: cell %d is %g, want %g", w, h, rows[0], rows[1], i, out[i], want[i])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package gogol
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DefaultPalette draws dead cells black and live cells white.
+var DefaultPalette = color.Palette{color.Black, color.White}
+
+// GIFOptions configure a GIFWriter.
+type GIFOptions struct {
+	Delay     int           // delay between frames in 100ths of a second
+	Scale     int           // pixels per cell, 1 if not set
+	Palette   color.Palette // colours of the states, DefaultPalette if not set
+	Skip      int           // generations skipped between recorded frames
+	LoopCount int           // 0 loops forever, -1 plays once, n repeats n times
+}
+
+// GIFWriter records generations of a simulation and encodes them as an
+// animated GIF when closed.
+type GIFWriter struct {
+	w    io.Writer
+	opts GIFOptions
+	anim gif.GIF
+	n    int // generations seen
+}
+
+// NewGIFWriter returns a GIFWriter that writes to w.
+func NewGIFWriter(w io.Writer, opts GIFOptions) *GIFWriter {
+	if opts.Scale < 1 {
+		opts.Scale = 1
+	}
+	if len(opts.Palette) < 2 {
+		opts.Palette = DefaultPalette
+	}
+	return &GIFWriter{w: w, opts: opts, anim: gif.GIF{LoopCount: opts.LoopCount}}
+}
+
+// Add records the current state of e, unless it falls in the skipped
+// generations. Multi-state worlds with more states than colours in the
+// palette are drawn with their own colours or StatePalette.
+func (g *GIFWriter) Add(e World) {
+	n := g.n
+	g.n++
+	if n%(g.opts.Skip+1) != 0 {
+		return
+	}
+	p := g.opts.Palette
+	if sw, ok := multiState(e); ok && len(p) < sw.StateCount() {
+		p = paletteOf(sw)
+	}
+	g.anim.Image = append(g.anim.Image, Paletted(e, g.opts.Scale, p))
+	g.anim.Delay = append(g.anim.Delay, g.opts.Delay)
+}
+
+// Close encodes the recorded frames. It does not close the underlying writer.
+func (g *GIFWriter) Close() error {
+	if len(g.anim.Image) == 0 {
+		return fmt.Errorf("gogol: no frames to encode")
+	}
+	return gif.EncodeAll(g.w, &g.anim)
+}
+
+// Paletted draws e with scale x scale pixels per cell, using p[0] for dead
+// cells and p[1] for live ones. The cells of multi-state worlds use the
+// colour of their state, or the last colour of p if there are not enough.
+func Paletted(e World, scale int, p color.Palette) *image.Paletted {
+	r := e.Bounds()
+	img := image.NewPaletted(image.Rect(0, 0, r.Dx()*scale, r.Dy()*scale), p)
+	sw, multi := multiState(e)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if !e.Get(x, y) {
+				continue
+			}
+			idx := uint8(1)
+			if multi {
+				idx = min(sw.State(x, y), uint8(len(p)-1))
+			}
+			px, py := (x-r.Min.X)*scale, (y-r.Min.Y)*scale
+			for dy := 0; dy < scale; dy++ {
+				row := img.Pix[(py+dy)*img.Stride+px:]
+				for dx := 0; dx < scale; dx++ {
+					row[dx] = idx
+				}
+			}
+		}
+	}
+	return img
+}
+
+// ParsePalette parses a comma separated list of hex colours such as
+// "000000,ffffff".
+func ParsePalette(s string) (color.Palette, error) {
+	var p color.Palette
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimPrefix(strings.TrimSpace(f), "#")
+		v, err := strconv.ParseUint(f, 16, 32)
+		if err != nil || len(f) != 6 {
+			return nil, fmt.Errorf("gogol: invalid colour %q", f)
+		}
+		p = append(p, color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 255})
+	}
+	if len(p) < 2 {
+		return nil, fmt.Errorf("gogol: palette needs at least 2 colours, got %d", len(p))
+	}
+	return p, nil
+}
@@ -0,0 +1,129 @@
+package gogol
+
+import (
+	"bytes"
+	"image/color"
+	"image/gif"
+	"testing"
+)
+
+func TestGIFWriter(t *testing.T) {
+	p, err := ParsePalette("102030,#a0b0c0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewNaive(Config{Width: 5, Height: 4})
+	e.Set(1, 2, true)
+	var buf bytes.Buffer
+	w := NewGIFWriter(&buf, GIFOptions{Delay: 7, Scale: 3, Palette: p, Skip: 2, LoopCount: 4})
+	// generations 0, 3 and 6 of 7 are recorded
+	for i := 0; i < 7; i++ {
+		w.Add(e)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	g, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Image) != 3 {
+		t.Fatalf("%d frames, want 3", len(g.Image))
+	}
+	if g.LoopCount != 4 {
+		t.Errorf("loop count %d, want 4", g.LoopCount)
+	}
+	for i, img := range g.Image {
+		if g.Delay[i] != 7 {
+			t.Errorf("frame %d: delay %d, want 7", i, g.Delay[i])
+		}
+		if b := img.Bounds(); b.Dx() != 15 || b.Dy() != 12 {
+			t.Errorf("frame %d: size %v, want 15x12", i, b.Size())
+		}
+	}
+	img := g.Image[0]
+	dead, alive := color.RGBA{0x10, 0x20, 0x30, 255}, color.RGBA{0xa0, 0xb0, 0xc0, 255}
+	// every pixel of the 3x3 block of cell (1, 2) is alive
+	for y := 0; y < 12; y++ {
+		for x := 0; x < 15; x++ {
+			want := dead
+			if x/3 == 1 && y/3 == 2 {
+				want = alive
+			}
+			if got := color.RGBAModel.Convert(img.At(x, y)); got != want {
+				t.Fatalf("pixel (%d, %d) is %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestGIFWriterDefaults(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewGIFWriter(&buf, GIFOptions{LoopCount: -1})
+	if err := w.Close(); err == nil {
+		t.Error("encoded a GIF without frames")
+	}
+	e := NewNaive(Config{Width: 2, Height: 1})
+	e.Set(0, 0, true)
+	w.Add(e)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	g, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// black and white, one pixel per cell, played once
+	img := g.Image[0]
+	if img.Bounds().Dx() != 2 || img.Bounds().Dy() != 1 || g.LoopCount != -1 {
+		t.Errorf("size %v, loop count %d", img.Bounds().Size(), g.LoopCount)
+	}
+	white, black := color.RGBA{255, 255, 255, 255}, color.RGBA{0, 0, 0, 255}
+	if color.RGBAModel.Convert(img.At(0, 0)) != white || color.RGBAModel.Convert(img.At(1, 0)) != black {
+		t.Errorf("cells drawn %v and %v", img.At(0, 0), img.At(1, 0))
+	}
+}
+
+func TestGIFWriterStates(t *testing.T) {
+	// Brian's Brain has three states and the palette two colours, so the
+	// states get the colours of StatePalette
+	r := MustParseRule("/2/3")
+	e := NewGenerations(Config{Width: 3, Height: 1, Rule: &r})
+	e.SetState(1, 0, 1)
+	e.SetState(2, 0, 2)
+	var buf bytes.Buffer
+	w := NewGIFWriter(&buf, GIFOptions{})
+	w.Add(e)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	g, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := StatePalette(3)
+	for x := 0; x < 3; x++ {
+		want := color.RGBAModel.Convert(p[e.State(x, 0)])
+		if got := color.RGBAModel.Convert(g.Image[0].At(x, 0)); got != want {
+			t.Errorf("state %d drawn %v, want %v", e.State(x, 0), got, want)
+		}
+	}
+}
+
+func TestParsePalette(t *testing.T) {
+	p, err := ParsePalette(" 000000, #FF8000 ,0000ff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := color.Palette{color.RGBA{0, 0, 0, 255}, color.RGBA{255, 128, 0, 255}, color.RGBA{0, 0, 255, 255}}
+	for i := range want {
+		if p[i] != want[i] {
+			t.Errorf("colour %d is %v, want %v", i, p[i], want[i])
+		}
+	}
+	for _, s := range []string{"", "000000", "000000,fff", "000000,fffffff", "000000,ggg000", "000000,,ffffff", "000000;ffffff"} {
+		if p, err := ParsePalette(s); err == nil {
+			t.Errorf("%q parsed as %v", s, p)
+		}
+	}
+}
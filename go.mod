@@ -0,0 +1,3 @@
+module github.com/juansensio/gogol
+
+go 1.22
@@ -0,0 +1,130 @@
+package gogol
+
+import "image"
+
+// Grid is a dense world used to move cells in and out of engines.
+// Multi-state grids also keep the state of every cell in States, with Cells
+// set for the cells that are not dead.
+type Grid struct {
+	Width     int
+	Height    int
+	Cells     [][]bool  // Cells[y][x]
+	States    [][]uint8 // States[y][x], nil for two-state grids
+	NumStates int       // number of states of a multi-state grid
+}
+
+// NewGrid returns a w x h grid of dead cells.
+func NewGrid(w, h int) *Grid {
+	return &Grid{Width: w, Height: h, Cells: newCells(w, h)}
+}
+
+func (g *Grid) Get(x, y int) bool {
+	if x < 0 || x >= g.Width || y < 0 || y >= g.Height {
+		return false
+	}
+	return g.Cells[y][x]
+}
+
+func (g *Grid) Set(x, y int, alive bool) {
+	if x < 0 || x >= g.Width || y < 0 || y >= g.Height {
+		return
+	}
+	g.Cells[y][x] = alive
+	if g.States != nil {
+		g.States[y][x] = 0
+		if alive {
+			g.States[y][x] = 1
+		}
+	}
+}
+
+// StateCount returns the number of states of the grid, 2 unless it is a
+// multi-state grid.
+func (g *Grid) StateCount() int {
+	if g.States == nil || g.NumStates < 2 {
+		return 2
+	}
+	return g.NumStates
+}
+
+// State returns the state of the cell at (x, y), 1 for live cells of
+// two-state grids.
+func (g *Grid) State(x, y int) uint8 {
+	if x < 0 || x >= g.Width || y < 0 || y >= g.Height {
+		return 0
+	}
+	if g.States == nil {
+		if g.Cells[y][x] {
+			return 1
+		}
+		return 0
+	}
+	return g.States[y][x]
+}
+
+// SetState sets the state of the cell at (x, y), turning g into a
+// multi-state grid.
+func (g *Grid) SetState(x, y int, s uint8) {
+	if x < 0 || x >= g.Width || y < 0 || y >= g.Height {
+		return
+	}
+	g.multi()
+	g.States[y][x] = s
+	g.Cells[y][x] = s != 0
+	if int(s) >= g.NumStates {
+		g.NumStates = int(s) + 1
+	}
+}
+
+func (g *Grid) Population() int {
+	return countCells(g.Cells)
+}
+
+func (g *Grid) Bounds() image.Rectangle {
+	return image.Rect(0, 0, g.Width, g.Height)
+}
+
+// Load copies g into e, cells outside the bounds of e are dropped.
+func Load(e Engine, g *Grid) {
+	LoadAt(e, g, 0, 0)
+}
+
+// multi allocates the states of a two-state grid.
+func (g *Grid) multi() {
+	if g.States != nil {
+		return
+	}
+	g.States = make([][]uint8, g.Height)
+	for j := range g.States {
+		g.States[j] = make([]uint8, g.Width)
+		for i, alive := range g.Cells[j] {
+			if alive {
+				g.States[j][i] = 1
+			}
+		}
+	}
+}
+
+// Snapshot returns a copy of the cells of e, with their states if e is a
+// StateWorld.
+func Snapshot(e World) *Grid {
+	r := e.Bounds()
+	g := NewGrid(r.Dx(), r.Dy())
+	sw, multi := e.(StateWorld)
+	if multi && sw.StateCount() > 2 {
+		g.multi()
+		g.NumStates = sw.StateCount()
+	} else {
+		multi = false
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if multi {
+				g.SetState(x-r.Min.X, y-r.Min.Y, sw.State(x, y))
+			} else {
+				g.Cells[y-r.Min.Y][x-r.Min.X] = e.Get(x, y)
+			}
+		}
+	}
+	return g
+}
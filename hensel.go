@@ -0,0 +1,149 @@
+package gogol
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// Isotropic non-totalistic rules look at the configuration of the 8
+// neighbours, not only at how many are alive. Configurations are bytes with
+// one bit per neighbour in reading order: NW, N, NE, W, E, SW, S, SE.
+
+// henselLetters are the letters of each neighbour count in Hensel notation.
+var henselLetters = [9]string{
+	0: "",
+	1: "ce",
+	2: "ceaikn",
+	3: "ceaiknjqry",
+	4: "ceaiknjqrtwyz",
+	5: "ceaiknjqry",
+	6: "ceaikn",
+	7: "ce",
+	8: "",
+}
+
+// henselShapes are a configuration for each letter of 1 to 4 neighbours,
+// the ones for 5 to 8 neighbours are their complements.
+var henselShapes = [5][]uint8{
+	1: {0x01, 0x02},
+	2: {0x05, 0x0a, 0x03, 0x18, 0x11, 0x24},
+	3: {0x25, 0x1a, 0x0b, 0x07, 0x32, 0x0d, 0x0e, 0x26, 0x19, 0x31},
+	4: {0xa5, 0x5a, 0x0f, 0x1d, 0x33, 0x27, 0x3a, 0x36, 0x1b, 0x35, 0x39, 0x2e, 0x3c},
+}
+
+// henselShape returns a configuration of n neighbours with the given letter.
+func henselShape(n int, letter byte) uint8 {
+	if n <= 4 {
+		return henselShapes[n][strings.IndexByte(henselLetters[n], letter)]
+	}
+	return ^henselShapes[8-n][strings.IndexByte(henselLetters[8-n], letter)]
+}
+
+// neighbourOffsets are the (dx, dy) of the bits of a configuration.
+var neighbourOffsets = [8][2]int{{-1, -1}, {0, -1}, {1, -1}, {-1, 0}, {1, 0}, {-1, 1}, {0, 1}, {1, 1}}
+
+// symmetries returns the configurations obtained by rotating and reflecting c.
+func symmetries(c uint8) [8]uint8 {
+	var out [8]uint8
+	transforms := [8]func(x, y int) (int, int){
+		func(x, y int) (int, int) { return x, y },
+		func(x, y int) (int, int) { return -y, x },
+		func(x, y int) (int, int) { return -x, -y },
+		func(x, y int) (int, int) { return y, -x },
+		func(x, y int) (int, int) { return -x, y },
+		func(x, y int) (int, int) { return x, -y },
+		func(x, y int) (int, int) { return y, x },
+		func(x, y int) (int, int) { return -y, -x },
+	}
+	for t, f := range transforms {
+		for b, o := range neighbourOffsets {
+			if c&(1<<b) == 0 {
+				continue
+			}
+			x, y := f(o[0], o[1])
+			for k, p := range neighbourOffsets {
+				if p[0] == x && p[1] == y {
+					out[t] |= 1 << k
+				}
+			}
+		}
+	}
+	return out
+}
+
+// parseHensel parses one half of a rule, such as "2ce3ai" or "23-k", into
+// the set of configurations it contains.
+func parseHensel(s string, t *[256]bool) error {
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < '0' || c > '8' {
+			return fmt.Errorf("invalid neighbour count %q", c)
+		}
+		n := int(c - '0')
+		i++
+		negate := i < len(s) && s[i] == '-'
+		if negate {
+			i++
+		}
+		start := i
+		for i < len(s) && s[i] >= 'a' && s[i] <= 'z' {
+			if strings.IndexByte(henselLetters[n], s[i]) < 0 {
+				return fmt.Errorf("invalid letter %q for %d neighbours", s[i], n)
+			}
+			i++
+		}
+		set := s[start:i]
+		if set == "" {
+			if negate {
+				return fmt.Errorf("missing letters after %d-", n)
+			}
+			for cfg := 0; cfg < 256; cfg++ {
+				if bits.OnesCount8(uint8(cfg)) == n {
+					t[cfg] = true
+				}
+			}
+			continue
+		}
+		for k := 0; k < len(henselLetters[n]); k++ {
+			l := henselLetters[n][k]
+			if (strings.IndexByte(set, l) >= 0) == negate {
+				continue
+			}
+			for _, cfg := range symmetries(henselShape(n, l)) {
+				t[cfg] = true
+			}
+		}
+	}
+	return nil
+}
+
+// henselString writes the counts and letters of one half of a rule.
+func henselString(sb *strings.Builder, t *[256]bool) {
+	for n := 0; n <= 8; n++ {
+		var in, out string
+		for k := 0; k < len(henselLetters[n]); k++ {
+			if t[henselShape(n, henselLetters[n][k])] {
+				in += string(henselLetters[n][k])
+			} else {
+				out += string(henselLetters[n][k])
+			}
+		}
+		switch {
+		case henselLetters[n] == "":
+			if t[uint8(0xff>>(8-n))] {
+				sb.WriteByte('0' + byte(n))
+			}
+		case out == "":
+			sb.WriteByte('0' + byte(n))
+		case in == "":
+		case len(in) <= len(out):
+			sb.WriteByte('0' + byte(n))
+			sb.WriteString(in)
+		default:
+			sb.WriteByte('0' + byte(n))
+			sb.WriteByte('-')
+			sb.WriteString(out)
+		}
+	}
+}
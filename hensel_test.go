@@ -0,0 +1,163 @@
+package gogol
+
+import (
+	"io"
+	"math/bits"
+	"strings"
+	"testing"
+)
+
+// picture returns the configuration drawn by three rows of '*' and '.'
+// around the centre 'o', such as "*.*/.o./...".
+func picture(s string) uint8 {
+	var cfg uint8
+	b := 0
+	for _, c := range strings.ReplaceAll(s, "/", "") {
+		switch c {
+		case 'o':
+			continue
+		case '*':
+			cfg |= 1 << b
+		}
+		b++
+	}
+	return cfg
+}
+
+// henselPictures are a configuration of each letter of 1 to 4 neighbours,
+// drawn from the neighbourhoods of Golly's liferules.cpp rather than from
+// henselShapes.
+var henselPictures = map[string]string{
+	"1c": "*../.o./...",
+	"1e": ".*./.o./...",
+	"2c": "*.*/.o./...",
+	"2e": ".*./*o./...",
+	"2a": "**./.o./...",
+	"2i": ".../*o*/...",
+	"2k": "*../.o*/...",
+	"2n": "..*/.o./*..",
+	"3c": "*.*/.o./*..",
+	"3e": ".*./*o*/...",
+	"3a": "**./*o./...",
+	"3i": "***/.o./...",
+	"3k": ".*./.o*/*..",
+	"3n": "*.*/*o./...",
+	"3j": ".**/*o./...",
+	"3q": ".**/.o./*..",
+	"3r": "*../*o*/...",
+	"3y": "*../.o*/*..",
+	"4c": "*.*/.o./*.*",
+	"4e": ".*./*o*/.*.",
+	"4a": "***/*o./...",
+	"4i": "*.*/*o*/...",
+	"4k": "**./.o*/*..",
+	"4n": "***/.o./*..",
+	"4j": ".*./*o*/*..",
+	"4q": ".**/.o*/*..",
+	"4r": "**./*o*/...",
+	"4t": "*.*/.o*/*..",
+	"4w": "*../*o*/*..",
+	"4y": ".**/*o./*..",
+	"4z": "..*/*o*/*..",
+}
+
+func TestHenselLetters(t *testing.T) {
+	for name, pic := range henselPictures {
+		n := int(name[0] - '0')
+		cfg := picture(pic)
+		if bits.OnesCount8(cfg) != n {
+			t.Fatalf("%s: picture has %d cells", name, bits.OnesCount8(cfg))
+		}
+		r := MustParseRule("B" + name + "/S")
+		// the picture, turned and mirrored, is born, and no other
+		// configuration of n cells
+		want := map[uint8]bool{}
+		for _, s := range symmetries(cfg) {
+			want[s] = true
+		}
+		for c := 0; c < 256; c++ {
+			if bits.OnesCount8(uint8(c)) != n {
+				continue
+			}
+			if got := r.NextConfig(false, n, uint8(c)); got != want[uint8(c)] {
+				t.Errorf("%s: configuration %08b born %t", name, c, got)
+			}
+		}
+		// with more than 4 neighbours the complement carries the same letter
+		if n == 4 {
+			continue
+		}
+		r = MustParseRule("B" + string(rune('0'+8-n)) + name[1:] + "/S")
+		if !r.NextConfig(false, 8-n, ^cfg) {
+			t.Errorf("%d%s: complement of %s not born", 8-n, name[1:], name)
+		}
+	}
+}
+
+func TestHenselPartition(t *testing.T) {
+	// the letters of each count split its configurations
+	for n := 1; n <= 7; n++ {
+		seen := map[uint8]byte{}
+		for _, l := range []byte(henselLetters[n]) {
+			r := MustParseRule("B" + string(rune('0'+n)) + string(l) + "/S")
+			for c := 0; c < 256; c++ {
+				if !r.NextConfig(false, n, uint8(c)) {
+					continue
+				}
+				if bits.OnesCount8(uint8(c)) != n {
+					t.Fatalf("%d%c holds %08b", n, l, c)
+				}
+				if prev, ok := seen[uint8(c)]; ok {
+					t.Fatalf("%08b is both %d%c and %d%c", c, n, prev, n, l)
+				}
+				seen[uint8(c)] = l
+			}
+		}
+		total := 0
+		for c := 0; c < 256; c++ {
+			if bits.OnesCount8(uint8(c)) == n {
+				total++
+			}
+		}
+		if len(seen) != total {
+			t.Errorf("%d neighbours: letters hold %d of %d configurations", n, len(seen), total)
+		}
+	}
+}
+
+func TestHenselBlinker(t *testing.T) {
+	// Without S2i the centre of a blinker, with neighbours only at its
+	// sides, dies: the births above and below it are left alone and die too
+	r := MustParseRule("B3/S2-i3")
+	for _, name := range Engines() {
+		c := Config{Width: 5, Height: 5, Rule: &r}
+		e, err := New(name, c)
+		if err != nil {
+			continue
+		}
+		if cl, ok := e.(io.Closer); ok {
+			defer cl.Close()
+		}
+		for x := 1; x <= 3; x++ {
+			e.Set(x, 2, true)
+		}
+		e.Step()
+		if e.Population() != 2 || !e.Get(2, 1) || !e.Get(2, 3) {
+			t.Errorf("%s: generation 1 has %d cells", name, e.Population())
+		}
+		e.Step()
+		if e.Population() != 0 {
+			t.Errorf("%s: generation 2 has %d cells", name, e.Population())
+		}
+	}
+	// with S2i it is the blinker of Life
+	r = MustParseRule("B3/S2i3")
+	e := NewNaive(Config{Width: 5, Height: 5, Rule: &r})
+	for x := 1; x <= 3; x++ {
+		e.Set(x, 2, true)
+	}
+	e.StepN(2)
+	if e.Population() != 3 || !e.Get(1, 2) || !e.Get(3, 2) {
+		t.Errorf("B3/S2i3 blinker has %d cells after its period", e.Population())
+	}
+}
@@ -0,0 +1,170 @@
+package gogol
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Neighborhood is the shape of the neighbourhood of a cell.
+type Neighborhood byte
+
+const (
+	Moore      Neighborhood = 'M' // square
+	VonNeumann Neighborhood = 'N' // diamond
+	Circular   Neighborhood = 'C' // disk
+)
+
+// MaxRadius is the largest neighbourhood radius of Larger than Life rules.
+const MaxRadius = 20
+
+// span returns the half width of the row dy of a neighbourhood of radius r,
+// the row covers the columns -span to span. As in Golly, the circular
+// neighbourhood holds the cells with dx²+dy² <= r²+r, those whose centres
+// are within about r+1/2 of the cell.
+func (n Neighborhood) span(r, dy int) int {
+	if dy < 0 {
+		dy = -dy
+	}
+	switch n {
+	case VonNeumann:
+		return r - dy
+	case Circular:
+		return int(math.Sqrt(float64(r*r + r - dy*dy)))
+	}
+	return r
+}
+
+// Stencil returns the offsets of the cells in a neighbourhood of radius r,
+// row by row. The center is only included if middle is set.
+func (n Neighborhood) Stencil(r int, middle bool) []image.Point {
+	var s []image.Point
+	for dy := -r; dy <= r; dy++ {
+		w := n.span(r, dy)
+		for dx := -w; dx <= w; dx++ {
+			if dx == 0 && dy == 0 && !middle {
+				continue
+			}
+			s = append(s, image.Pt(dx, dy))
+		}
+	}
+	return s
+}
+
+// LtL is a Larger than Life rule: cells count the live cells in a
+// neighbourhood of the given radius and shape, and are born or survive when
+// the count falls in an inclusive range.
+type LtL struct {
+	Radius       int
+	Middle       bool // the cell counts itself
+	Neighborhood Neighborhood
+	Birth        [2]int // min and max live neighbours for a dead cell to be born
+	Survive      [2]int // min and max live neighbours for a live cell to survive
+}
+
+// Size returns the number of cells counted, the largest possible count.
+func (l *LtL) Size() int {
+	return len(l.Neighborhood.Stencil(l.Radius, l.Middle))
+}
+
+// parseLtL parses a rule in Golly's Larger than Life notation, like
+// "R5,C0,M1,S34..58,B34..45,NM".
+func parseLtL(s string) (Rule, error) {
+	l := &LtL{Neighborhood: Moore}
+	r := Rule{States: 2, ltl: l}
+	seen := map[byte]bool{}
+	for _, f := range strings.Split(strings.ToUpper(s), ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			return r, fmt.Errorf("gogol: invalid rule %q", s)
+		}
+		k, v := f[0], f[1:]
+		seen[k] = true
+		var err error
+		switch k {
+		case 'R':
+			l.Radius, err = strconv.Atoi(v)
+			if err == nil && (l.Radius < 1 || l.Radius > MaxRadius) {
+				err = fmt.Errorf("radius must be between 1 and %d", MaxRadius)
+			}
+		case 'C':
+			r.States, err = strconv.Atoi(v)
+			if err == nil && (r.States < 0 || r.States > 256) {
+				err = fmt.Errorf("the number of states must be between 0 and 256")
+			}
+			r.States = max(r.States, 2)
+		case 'M':
+			if v != "0" && v != "1" {
+				err = fmt.Errorf("M must be 0 or 1")
+			}
+			l.Middle = v == "1"
+		case 'S':
+			l.Survive, err = parseRange(v)
+		case 'B':
+			l.Birth, err = parseRange(v)
+		case 'N':
+			if len(v) == 1 {
+				l.Neighborhood = Neighborhood(v[0])
+			}
+			if len(v) != 1 || l.Neighborhood != Moore && l.Neighborhood != VonNeumann && l.Neighborhood != Circular {
+				err = fmt.Errorf("unknown neighbourhood %q", v)
+			}
+		default:
+			err = fmt.Errorf("unknown field %q", f)
+		}
+		if err != nil {
+			return r, fmt.Errorf("gogol: invalid rule %q: %v", s, err)
+		}
+	}
+	for _, k := range []byte("RSB") {
+		if !seen[k] {
+			return r, fmt.Errorf("gogol: invalid rule %q: missing %c", s, k)
+		}
+	}
+	return r, nil
+}
+
+// parseRange parses "min..max" or a single count.
+func parseRange(s string) ([2]int, error) {
+	lo, hi, ok := strings.Cut(s, "..")
+	if !ok {
+		hi = lo
+	}
+	a, err1 := strconv.Atoi(lo)
+	b, err2 := strconv.Atoi(hi)
+	if err1 != nil || err2 != nil || a < 0 || b < a {
+		return [2]int{}, fmt.Errorf("invalid range %q", s)
+	}
+	return [2]int{a, b}, nil
+}
+
+// format returns the rule in Golly's notation.
+func (l *LtL) format(states int) string {
+	c, m := 0, 0
+	if states > 2 {
+		c = states
+	}
+	if l.Middle {
+		m = 1
+	}
+	return fmt.Sprintf("R%d,C%d,M%d,S%d..%d,B%d..%d,N%c",
+		l.Radius, c, m, l.Survive[0], l.Survive[1], l.Birth[0], l.Birth[1], l.Neighborhood)
+}
+
+// countTables returns the birth and survive tables of a totalistic rule,
+// indexed by the number of live cells counted.
+func countTables(r *Rule) (birth, survive []bool) {
+	if r.ltl == nil {
+		return r.Birth[:], r.Survive[:]
+	}
+	n := r.ltl.Size()
+	birth = make([]bool, n+1)
+	survive = make([]bool, n+1)
+	for i := range birth {
+		birth[i] = i >= r.ltl.Birth[0] && i <= r.ltl.Birth[1]
+		survive[i] = i >= r.ltl.Survive[0] && i <= r.ltl.Survive[1]
+	}
+	return birth, survive
+}
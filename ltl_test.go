@@ -0,0 +1,73 @@
+package gogol
+
+import (
+	"image"
+	"testing"
+)
+
+func TestStencilSize(t *testing.T) {
+	tests := []struct {
+		n    Neighborhood
+		r    int
+		want int
+	}{
+		{Moore, 1, 9},
+		{Moore, 5, 121},
+		{VonNeumann, 1, 5},
+		{VonNeumann, 3, 25},
+		// Golly's circular neighbourhoods, x²+y² <= r²+r
+		{Circular, 1, 9},
+		{Circular, 2, 21},
+		{Circular, 3, 37},
+	}
+	for _, tt := range tests {
+		if got := len(tt.n.Stencil(tt.r, true)); got != tt.want {
+			t.Errorf("N%c radius %d: got %d cells, want %d", tt.n, tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestCircularBirth(t *testing.T) {
+	// Cells with one neighbour are born and lonely cells survive, so a single
+	// cell grows into the neighbourhood: the 21 cells of the radius 2 disk,
+	// the rows 3, 5, 5, 5 and 3 cells wide
+	r := MustParseRule("R2,C0,M0,S0..0,B1..1,NC")
+	want := map[image.Point]bool{}
+	for dy, w := range []int{1, 2, 2, 2, 1} {
+		for dx := -w; dx <= w; dx++ {
+			want[image.Pt(4+dx, 4+dy-2)] = true
+		}
+	}
+	for _, name := range []string{"ltl", "matrix", "fft"} {
+		e, err := New(name, Config{Width: 9, Height: 9, Rule: &r})
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.Set(4, 4, true)
+		e.Step()
+		if got := e.Population(); got != len(want) {
+			t.Errorf("%s: got %d cells, want %d", name, got, len(want))
+		}
+		for p := range want {
+			if !e.Get(p.X, p.Y) {
+				t.Errorf("%s: cell %v is dead", name, p)
+			}
+		}
+	}
+}
+
+func TestReferenceRule(t *testing.T) {
+	tests := map[string]string{
+		"B3/S23":        "naive",
+		"B2ce3ai/S23-k": "naive",
+		"B2/S/C3":       "generations",
+		"Bosco":         "ltl",
+		"WireWorld":     "table",
+		"Orbium":        "lenia",
+	}
+	for rule, want := range tests {
+		if got := Reference(MustParseRule(rule)); got != want {
+			t.Errorf("%s: got reference %s, want %s", rule, got, want)
+		}
+	}
+}
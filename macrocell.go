@@ -0,0 +1,231 @@
+package gogol
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ReadMacrocell reads a pattern in Golly's macrocell (.mc) format. The
+// pattern is kept as a quadtree in p.Tree and p.Grid is nil, so huge
+// patterns never need a dense grid.
+func ReadMacrocell(r io.Reader) (*Pattern, error) {
+	p := &Pattern{}
+	q := NewQuadtree()
+	nodes := []*Node{nil} // nodes are numbered from 1, 0 is an empty node
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<24)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		switch {
+		case line == "", strings.HasPrefix(line, "[M2]"):
+		case strings.HasPrefix(line, "#"):
+			if len(line) < 2 {
+				continue
+			}
+			text := strings.TrimSpace(line[2:])
+			switch line[1] {
+			case 'R':
+				p.Rule = text
+			case 'N':
+				p.Name = text
+			case 'O':
+				p.Author = text
+			case 'C', 'D':
+				p.Comments = append(p.Comments, text)
+			}
+		case line[0] == '.' || line[0] == '*' || line[0] == '$':
+			n, err := q.leaf8(line)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, n)
+		default:
+			n, err := q.parseNode(line, nodes)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, n)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 1 {
+		return nil, fmt.Errorf("gogol: macrocell: no nodes")
+	}
+	// the root is the last node, centered on the origin
+	q.Root = nodes[len(nodes)-1]
+	half := 1 << (q.Root.Level - 1)
+	q.Origin = image.Pt(-half, -half)
+	p.Tree = q
+	return p, nil
+}
+
+// leaf8 builds the level 3 node of an 8x8 leaf line such as "$.*$..*$***$".
+func (q *Quadtree) leaf8(line string) (*Node, error) {
+	var cells [8][8]bool
+	x, y := 0, 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '.':
+			x++
+		case '*':
+			if x >= 8 || y >= 8 {
+				return nil, fmt.Errorf("gogol: macrocell: leaf %q is larger than 8x8", line)
+			}
+			cells[y][x] = true
+			x++
+		case '$':
+			x = 0
+			y++
+		default:
+			return nil, fmt.Errorf("gogol: macrocell: invalid leaf %q", line)
+		}
+	}
+	var block func(level, x, y int) *Node
+	block = func(level, x, y int) *Node {
+		if level == 0 {
+			return q.Leaf(cells[y][x])
+		}
+		half := 1 << (level - 1)
+		return q.Node(block(level-1, x, y), block(level-1, x+half, y),
+			block(level-1, x, y+half), block(level-1, x+half, y+half))
+	}
+	return block(3, 0, 0), nil
+}
+
+// parseNode builds the node of a "k nw ne sw se" line.
+func (q *Quadtree) parseNode(line string, nodes []*Node) (*Node, error) {
+	f := strings.Fields(line)
+	if len(f) != 5 {
+		return nil, fmt.Errorf("gogol: macrocell: invalid node %q", line)
+	}
+	var v [5]int
+	for i := range f {
+		n, err := strconv.Atoi(f[i])
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("gogol: macrocell: invalid node %q", line)
+		}
+		v[i] = n
+	}
+	level := v[0]
+	if level < 1 {
+		return nil, fmt.Errorf("gogol: macrocell: invalid level in %q", line)
+	}
+	var c [4]*Node
+	for i := range c {
+		switch {
+		case level == 1: // children are cell states
+			c[i] = q.Leaf(v[i+1] != 0)
+		case v[i+1] == 0:
+			c[i] = q.Empty(level - 1)
+		case v[i+1] < len(nodes) && nodes[v[i+1]].Level == level-1:
+			c[i] = nodes[v[i+1]]
+		default:
+			return nil, fmt.Errorf("gogol: macrocell: invalid child in %q", line)
+		}
+	}
+	return q.Node(c[0], c[1], c[2], c[3]), nil
+}
+
+// WriteMacrocell writes p in Golly's macrocell (.mc) format.
+func WriteMacrocell(w io.Writer, p *Pattern) error {
+	q := p.Tree
+	if q == nil {
+		q = QuadtreeOf(p.Grid)
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "[M2] (gogol)")
+	rule := p.Rule
+	if rule == "" {
+		rule = "B3/S23"
+	}
+	fmt.Fprintf(bw, "#R %s\n", rule)
+	if p.Name != "" {
+		fmt.Fprintf(bw, "#N %s\n", p.Name)
+	}
+	if p.Author != "" {
+		fmt.Fprintf(bw, "#O %s\n", p.Author)
+	}
+	for _, c := range p.Comments {
+		fmt.Fprintf(bw, "#C %s\n", c)
+	}
+	if q.Root.Population == 0 {
+		bw.WriteString("$\n")
+		return bw.Flush()
+	}
+
+	// Leaves are 8x8, so smaller roots are grown around the cells first, in
+	// a copy with its own tables of nodes that leaves the tree of p as it was
+	t := *q
+	if t.Root.Level < 3 {
+		t.nodes, t.empty = maps.Clone(q.nodes), slices.Clone(q.empty)
+		for t.Root.Level < 3 {
+			t.expand()
+		}
+	}
+
+	// write the nodes children first, numbering them from 1
+	ids := make(map[*Node]int)
+	var write func(n *Node) int
+	write = func(n *Node) int {
+		if n.Population == 0 {
+			return 0
+		}
+		if id, ok := ids[n]; ok {
+			return id
+		}
+		if n.Level == 3 {
+			bw.WriteString(leafLine(n))
+		} else {
+			nw, ne, sw, se := write(n.NW), write(n.NE), write(n.SW), write(n.SE)
+			fmt.Fprintf(bw, "%d %d %d %d %d", n.Level, nw, ne, sw, se)
+		}
+		bw.WriteByte('\n')
+		ids[n] = len(ids) + 1
+		return ids[n]
+	}
+	write(t.Root)
+	return bw.Flush()
+}
+
+// leafLine returns the 8x8 leaf line of a level 3 node.
+func leafLine(n *Node) string {
+	var sb strings.Builder
+	for y := 0; y < 8; y++ {
+		row := make([]byte, 8)
+		for x := 0; x < 8; x++ {
+			row[x] = '.'
+			if nodeGet(n, x, y) {
+				row[x] = '*'
+			}
+		}
+		sb.WriteString(strings.TrimRight(string(row), "."))
+		sb.WriteByte('$')
+	}
+	return strings.TrimRight(sb.String(), "$") + "$"
+}
+
+// nodeGet returns the cell at (x, y) relative to the top-left corner of n.
+func nodeGet(n *Node, x, y int) bool {
+	for n.Level > 0 && n.Population > 0 {
+		half := 1 << (n.Level - 1)
+		switch {
+		case x < half && y < half:
+			n = n.NW
+		case y < half:
+			n, x = n.NE, x-half
+		case x < half:
+			n, y = n.SW, y-half
+		default:
+			n, x, y = n.SE, x-half, y-half
+		}
+	}
+	return n.Population == 1
+}
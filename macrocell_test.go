@@ -0,0 +1,76 @@
+package gogol
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// sameTree reports whether a and b have the same live cells.
+func sameTree(a, b *Quadtree) bool {
+	if a.Population() != b.Population() || a.Bounds() != b.Bounds() {
+		return false
+	}
+	same := true
+	a.Each(a.Bounds(), func(x, y int) {
+		same = same && b.Get(x, y)
+	})
+	return same
+}
+
+func TestMacrocellRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		mc   string
+	}{
+		{"level 1 root", "[M2] (golly)\n#R B3/S23\n1 1 1 1 0\n"},
+		{"level 2 root", "[M2] (golly)\n#R B3/S23\n1 0 1 1 1\n1 1 0 0 0\n2 2 0 0 1\n"},
+		{"level 3 root", "[M2] (golly)\n#R B3/S23\n.*$..*$***$\n"},
+		{"shared nodes", "[M2] (golly)\n#R B3/S23\n.*$..*$***$\n4 1 1 0 1\n5 2 0 2 2\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ReadMacrocell(strings.NewReader(tt.mc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			q := p.Tree
+			root, origin, nodes, empty := q.Root, q.Origin, len(q.nodes), len(q.empty)
+			var buf bytes.Buffer
+			if err := WriteMacrocell(&buf, p); err != nil {
+				t.Fatal(err)
+			}
+			// growing small roots must not add nodes to the tables of p
+			if q.Root != root || q.Origin != origin || len(q.nodes) != nodes || len(q.empty) != empty {
+				t.Errorf("writing changed the tree of the pattern: %d nodes and %d empty ones, were %d and %d",
+					len(q.nodes), len(q.empty), nodes, empty)
+			}
+			got, err := ReadMacrocell(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sameTree(got.Tree, p.Tree) {
+				t.Errorf("cells changed:\n%s", buf.String())
+			}
+			if got.Rule != p.Rule {
+				t.Errorf("got rule %q, want %q", got.Rule, p.Rule)
+			}
+		})
+	}
+}
+
+func TestMacrocellGrid(t *testing.T) {
+	// Dense patterns are written through a quadtree
+	p := &Pattern{Grid: glider(), Name: "Glider"}
+	var buf bytes.Buffer
+	if err := WritePattern(&buf, p, FormatMacro); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadPattern(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameCells(got.dense().Grid, p.Grid) || got.Name != "Glider" {
+		t.Errorf("got %v", got.dense().Grid.Cells)
+	}
+}
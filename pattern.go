@@ -0,0 +1,201 @@
+package gogol
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Pattern is a world read from or written to a pattern file. Macrocell
+// patterns are kept as a quadtree in Tree and have a nil Grid.
+type Pattern struct {
+	*Grid
+	Tree     *Quadtree
+	Name     string
+	Author   string
+	Comments []string
+	Rule     string // rulestring from the file, empty if not given
+}
+
+// LoadAt copies g into e with its top-left corner at (x, y), cells outside
+// the bounds of e are dropped unless e is Unbounded. The states of
+// multi-state grids are kept if e implements MultiState.
+func LoadAt(e Engine, g *Grid, x, y int) {
+	r := g.Bounds().Add(image.Pt(x, y))
+	if _, ok := e.(Unbounded); !ok {
+		r = r.Intersect(e.Bounds())
+	}
+	m, multi := e.(MultiState)
+	multi = multi && g.States != nil
+	for j := r.Min.Y; j < r.Max.Y; j++ {
+		for i := r.Min.X; i < r.Max.X; i++ {
+			if multi {
+				m.SetState(i, j, g.States[j-y][i-x])
+			} else {
+				e.Set(i, j, g.Cells[j-y][i-x])
+			}
+		}
+	}
+}
+
+// Size returns the width and height of the pattern.
+func (p *Pattern) Size() image.Point {
+	if p.Grid == nil {
+		return p.Tree.Bounds().Size()
+	}
+	return image.Pt(p.Width, p.Height)
+}
+
+// LoadPattern copies p into e with its top-left corner at (x, y).
+// Macrocell patterns are copied without building a dense grid.
+func LoadPattern(e Engine, p *Pattern, x, y int) {
+	if p.Grid == nil {
+		LoadQuadtree(e, p.Tree, x, y)
+		return
+	}
+	LoadAt(e, p.Grid, x, y)
+}
+
+// dense returns p with a Grid, building it from the quadtree if needed.
+func (p *Pattern) dense() *Pattern {
+	if p.Grid != nil {
+		return p
+	}
+	d := *p
+	r := p.Tree.Bounds()
+	d.Grid = NewGrid(r.Dx(), r.Dy())
+	p.Tree.Each(r, func(x, y int) {
+		d.Cells[y-r.Min.Y][x-r.Min.X] = true
+	})
+	return &d
+}
+
+// Format is a file format for worlds and patterns.
+type Format string
+
+const (
+	FormatRLE     Format = "rle"
+	FormatCells   Format = "cells"
+	FormatLife105 Format = "life105"
+	FormatLife106 Format = "life106"
+	FormatMacro   Format = "mc"
+	FormatText    Format = "text" // '@' and ' ' as printed in the terminal
+	FormatPNG     Format = "png"
+)
+
+// DetectFormat guesses the format of a pattern file from its first bytes.
+func DetectFormat(data []byte) Format {
+	if bytes.HasPrefix(data, []byte("\x89PNG")) {
+		return FormatPNG
+	}
+	s := string(data)
+	if strings.HasPrefix(s, "#Life 1.05") {
+		return FormatLife105
+	}
+	if strings.HasPrefix(s, "#Life 1.06") {
+		return FormatLife106
+	}
+	if strings.HasPrefix(s, "[M2]") {
+		return FormatMacro
+	}
+	cells := true
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "!"):
+			return FormatCells
+		case strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "x") && strings.Contains(line, "="):
+			return FormatRLE
+		default:
+			if strings.Trim(line, ".O") != "" {
+				cells = false
+			}
+		}
+	}
+	if cells {
+		return FormatCells
+	}
+	return FormatText
+}
+
+// FormatOf returns the format of a file from its extension, or "" if the
+// extension is unknown.
+func FormatOf(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".rle":
+		return FormatRLE
+	case ".cells":
+		return FormatCells
+	case ".lif", ".life":
+		return FormatLife106
+	case ".mc":
+		return FormatMacro
+	case ".txt":
+		return FormatText
+	case ".png":
+		return FormatPNG
+	}
+	return ""
+}
+
+// ReadPattern reads a pattern in any supported format, detecting it from
+// the content.
+func ReadPattern(r io.Reader) (*Pattern, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	br := bytes.NewReader(data)
+	switch DetectFormat(data) {
+	case FormatRLE:
+		return ReadRLE(br)
+	case FormatCells:
+		return ReadCells(br)
+	case FormatLife105:
+		return ReadLife105(br)
+	case FormatLife106:
+		return ReadLife106(br)
+	case FormatMacro:
+		return ReadMacrocell(br)
+	case FormatPNG:
+		g, err := ReadPNG(br)
+		if err != nil {
+			return nil, err
+		}
+		return &Pattern{Grid: g}, nil
+	}
+	g, err := ReadText(br)
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{Grid: g}, nil
+}
+
+// WritePattern writes p in format f.
+func WritePattern(w io.Writer, p *Pattern, f Format) error {
+	if f == FormatMacro {
+		return WriteMacrocell(w, p)
+	}
+	p = p.dense()
+	switch f {
+	case FormatRLE:
+		return WriteRLE(w, p)
+	case FormatCells:
+		return WriteCells(w, p)
+	case FormatLife105:
+		return WriteLife105(w, p)
+	case FormatLife106:
+		return WriteLife106(w, p)
+	case FormatText:
+		return WriteText(w, p.Grid)
+	case FormatPNG:
+		return png.Encode(w, Image(p.Grid))
+	}
+	return fmt.Errorf("gogol: unknown format %q", f)
+}
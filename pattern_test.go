@@ -0,0 +1,125 @@
+package gogol
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// glider returns a glider in a grid of its size.
+func glider() *Grid {
+	g := NewGrid(3, 3)
+	for _, p := range [][2]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}} {
+		g.Set(p[0], p[1], true)
+	}
+	return g
+}
+
+// sameCells reports whether a and b have the same size and cell states.
+func sameCells(a, b *Grid) bool {
+	if a.Width != b.Width || a.Height != b.Height {
+		return false
+	}
+	for y := 0; y < a.Height; y++ {
+		for x := 0; x < a.Width; x++ {
+			if a.State(x, y) != b.State(x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestReadRLE(t *testing.T) {
+	p, err := ReadRLE(strings.NewReader("#N Glider\n#O Richard K. Guy\nx = 3, y = 3, rule = B3/S23\nbo$2bo$3o!\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameCells(p.Grid, glider()) {
+		t.Error("glider read wrong")
+	}
+	if p.Name != "Glider" || p.Author != "Richard K. Guy" || p.Rule != "B3/S23" {
+		t.Errorf("got name %q, author %q, rule %q", p.Name, p.Author, p.Rule)
+	}
+}
+
+func TestRLERoundTrip(t *testing.T) {
+	wide := NewGrid(150, 4)
+	for x := 0; x < 150; x += 3 {
+		wide.Set(x, x%4, true)
+	}
+	multi := NewGrid(30, 10)
+	for i := 0; i < 300; i++ {
+		multi.SetState(i%30, i/30, uint8(i*7%256))
+	}
+	tests := []struct {
+		name string
+		p    *Pattern
+	}{
+		{"glider", &Pattern{Grid: glider(), Name: "Glider", Comments: []string{"a", "b"}}},
+		{"empty", &Pattern{Grid: NewGrid(5, 3)}},
+		{"wrapped lines", &Pattern{Grid: wide}},
+		{"multi-state", &Pattern{Grid: multi, Rule: "B2/S/C256"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteRLE(&buf, tt.p); err != nil {
+				t.Fatal(err)
+			}
+			for _, line := range strings.Split(buf.String(), "\n") {
+				if !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "x") && len(line) > rleLineLength {
+					t.Errorf("line of %d bytes", len(line))
+				}
+			}
+			got, err := ReadRLE(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sameCells(got.Grid, tt.p.Grid) {
+				t.Error("cells changed")
+			}
+			if got.Name != tt.p.Name || strings.Join(got.Comments, "\n") != strings.Join(tt.p.Comments, "\n") {
+				t.Errorf("got name %q and comments %q", got.Name, got.Comments)
+			}
+		})
+	}
+}
+
+func TestPatternRoundTrip(t *testing.T) {
+	// Life 1.05 and 1.06 only keep the live cells, so the pattern fills its
+	// bounds
+	p := &Pattern{Grid: glider(), Name: "Glider"}
+	for _, f := range []Format{FormatRLE, FormatCells, FormatLife105, FormatLife106, FormatText, FormatPNG} {
+		t.Run(string(f), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WritePattern(&buf, p, f); err != nil {
+				t.Fatal(err)
+			}
+			if got := DetectFormat(buf.Bytes()); got != f {
+				t.Errorf("detected %s", got)
+			}
+			got, err := ReadPattern(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sameCells(got.Grid, p.Grid) {
+				t.Errorf("cells changed:\n%s", buf.String())
+			}
+		})
+	}
+}
+
+func TestReadLife105Blocks(t *testing.T) {
+	p, err := ReadLife105(strings.NewReader("#Life 1.05\n#R 23/36\n#P -1 -1\n.*\n#P 1 0\n*\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Cells (0, -1) and (1, 0), moved to the origin
+	want := NewGrid(2, 2)
+	want.Set(0, 0, true)
+	want.Set(1, 1, true)
+	if !sameCells(p.Grid, want) || p.Rule != "23/36" {
+		t.Errorf("got %dx%d pattern with rule %q", p.Width, p.Height, p.Rule)
+	}
+}
@@ -0,0 +1,114 @@
+package gogol
+
+import "sync"
+
+// pool is a set of long-lived workers, started once with the engine instead
+// of every generation. Each call to run hands the same function to every
+// worker, which may loop over many generations meeting at a barrier between
+// phases.
+type pool struct {
+	workers int
+	tasks   []chan func(w int)
+	done    *barrier // workers and the caller of run
+	phase   *barrier // workers only
+	closed  sync.Once
+}
+
+func newPool(workers int) *pool {
+	p := &pool{
+		workers: workers,
+		tasks:   make([]chan func(w int), workers),
+		done:    newBarrier(workers + 1),
+		phase:   newBarrier(workers),
+	}
+	for w := range p.tasks {
+		p.tasks[w] = make(chan func(w int))
+		go worker(w, p.tasks[w], p.done)
+	}
+	return p
+}
+
+// worker runs the tasks sent to worker w until the channel is closed. It
+// does not keep a reference to the pool, so the engine owning the pool can be
+// collected and close it.
+func worker(w int, tasks chan func(w int), done *barrier) {
+	for f := range tasks {
+		f(w)
+		done.wait()
+	}
+}
+
+// run calls f on every worker with its index and waits until they all
+// return.
+func (p *pool) run(f func(w int)) {
+	for _, t := range p.tasks {
+		t <- f
+	}
+	p.done.wait()
+}
+
+// sync waits until every worker has called it, separating the phases of a
+// task.
+func (p *pool) sync() {
+	p.phase.wait()
+}
+
+// close stops the workers. It can be called more than once, by the Close
+// method of the engine and then by its finalizer.
+func (p *pool) close() {
+	p.closed.Do(func() {
+		for _, t := range p.tasks {
+			close(t)
+		}
+	})
+}
+
+// rows returns the rows from start to end of n handled by worker w.
+func (p *pool) rows(w, n int) (start, end int) {
+	return band(w, p.workers, n)
+}
+
+// band returns the rows from start to end of n given to worker w out of
+// workers. The remainder rows go one each to the first workers, so the bands
+// differ by one row at most.
+func band(w, workers, n int) (start, end int) {
+	rowsPerWorker, rest := n/workers, n%workers
+	start = w*rowsPerWorker + min(w, rest)
+	end = start + rowsPerWorker
+	if w < rest {
+		end++
+	}
+	return start, end
+}
+
+// barrier blocks the goroutines calling wait until n of them are waiting,
+// and can be used again right away.
+type barrier struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	n       int
+	waiting int
+	round   int
+}
+
+func newBarrier(n int) *barrier {
+	b := &barrier{n: n}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+func (b *barrier) wait() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	round := b.round
+	b.waiting++
+	if b.waiting == b.n {
+		b.waiting = 0
+		b.round++
+		b.cond.Broadcast()
+		return
+	}
+	for round == b.round {
+		b.cond.Wait()
+	}
+}
@@ -0,0 +1,61 @@
+package gogol
+
+import (
+	"io"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestBand(t *testing.T) {
+	// The bands cover the rows in order and differ by one row at most
+	for n := 0; n < 20; n++ {
+		for workers := 1; workers <= 8; workers++ {
+			next := 0
+			for w := 0; w < workers; w++ {
+				start, end := band(w, workers, n)
+				if start != next || end-start < n/workers || end-start > n/workers+1 {
+					t.Fatalf("%d rows, %d workers: worker %d gets %d to %d", n, workers, w, start, end)
+				}
+				next = end
+			}
+			if next != n {
+				t.Fatalf("%d rows, %d workers: %d rows covered", n, workers, next)
+			}
+		}
+	}
+}
+
+func TestCloseStopsWorkers(t *testing.T) {
+	names := []string{"parallel", "parallel2", "parallel_matrix", "parallel_bitpacked", "bands"}
+	before := runtime.NumGoroutine()
+	for i := 0; i < 20; i++ {
+		for _, name := range names {
+			e, err := New(name, Config{Width: 16, Height: 16, Workers: 4})
+			if err != nil {
+				t.Fatal(err)
+			}
+			e.StepN(2)
+			c, ok := e.(io.Closer)
+			if !ok {
+				t.Fatalf("%s does not implement io.Closer", name)
+			}
+			if err := c.Close(); err != nil {
+				t.Fatal(err)
+			}
+			// Closing twice, and the finalizer after Close, are harmless
+			if err := c.Close(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	runtime.GC()
+	// The workers return as soon as they see their channel closed
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("%d goroutines left running, %d before", n, before)
+	}
+}
@@ -0,0 +1,270 @@
+package gogol
+
+import "image"
+
+// Node is a node of a hashed quadtree. A node of level k covers
+// 2^k x 2^k cells, level 0 nodes are single cells. Nodes are canonical: two
+// nodes with the same cells are the same pointer, so they must not be
+// modified.
+type Node struct {
+	NW, NE, SW, SE *Node
+	Level          int
+	Population     int
+}
+
+// Quadtree is a world stored as a hashed quadtree, so huge and sparse
+// patterns only take memory for their distinct blocks.
+type Quadtree struct {
+	Root   *Node
+	Origin image.Point // position of the top-left corner of Root
+
+	nodes  map[[4]*Node]*Node
+	leaves [2]*Node
+	empty  []*Node // empty nodes by level
+}
+
+// NewQuadtree returns an empty quadtree.
+func NewQuadtree() *Quadtree {
+	q := &Quadtree{nodes: make(map[[4]*Node]*Node)}
+	q.leaves[0] = &Node{}
+	q.leaves[1] = &Node{Population: 1}
+	q.empty = []*Node{q.leaves[0]}
+	q.Root = q.Empty(3)
+	q.Origin = image.Pt(-4, -4)
+	return q
+}
+
+// Leaf returns the level 0 node of a cell.
+func (q *Quadtree) Leaf(alive bool) *Node {
+	if alive {
+		return q.leaves[1]
+	}
+	return q.leaves[0]
+}
+
+// Node returns the canonical node with the given children, which must all
+// have the same level.
+func (q *Quadtree) Node(nw, ne, sw, se *Node) *Node {
+	key := [4]*Node{nw, ne, sw, se}
+	if n, ok := q.nodes[key]; ok {
+		return n
+	}
+	n := &Node{
+		NW: nw, NE: ne, SW: sw, SE: se,
+		Level:      nw.Level + 1,
+		Population: nw.Population + ne.Population + sw.Population + se.Population,
+	}
+	q.nodes[key] = n
+	return n
+}
+
+// Nodes returns the number of distinct nodes stored in the tree.
+func (q *Quadtree) Nodes() int {
+	return len(q.nodes)
+}
+
+// Collect drops the stored nodes that cannot be reached from the root or
+// from keep, so the memory of blocks no longer in use can be reclaimed.
+// The nodes kept are the same pointers.
+func (q *Quadtree) Collect(keep ...*Node) {
+	nodes := make(map[[4]*Node]*Node)
+	var mark func(n *Node)
+	mark = func(n *Node) {
+		if n.Level == 0 {
+			return
+		}
+		key := [4]*Node{n.NW, n.NE, n.SW, n.SE}
+		if _, ok := nodes[key]; ok {
+			return
+		}
+		nodes[key] = n
+		mark(n.NW)
+		mark(n.NE)
+		mark(n.SW)
+		mark(n.SE)
+	}
+	mark(q.Root)
+	for _, n := range q.empty {
+		mark(n)
+	}
+	for _, n := range keep {
+		mark(n)
+	}
+	q.nodes = nodes
+}
+
+// has reports whether n is a node stored in q.
+func (q *Quadtree) has(n *Node) bool {
+	if n.Level == 0 {
+		return n == q.leaves[0] || n == q.leaves[1]
+	}
+	return q.nodes[[4]*Node{n.NW, n.NE, n.SW, n.SE}] == n
+}
+
+// canonical returns the node of q with the same cells as n, which may come
+// from another tree.
+func (q *Quadtree) canonical(n *Node, seen map[*Node]*Node) *Node {
+	if n.Level == 0 {
+		return q.Leaf(n.Population != 0)
+	}
+	if c, ok := seen[n]; ok {
+		return c
+	}
+	c := q.Node(q.canonical(n.NW, seen), q.canonical(n.NE, seen), q.canonical(n.SW, seen), q.canonical(n.SE, seen))
+	seen[n] = c
+	return c
+}
+
+// Empty returns the empty node of a level.
+func (q *Quadtree) Empty(level int) *Node {
+	for len(q.empty) <= level {
+		e := q.empty[len(q.empty)-1]
+		q.empty = append(q.empty, q.Node(e, e, e, e))
+	}
+	return q.empty[level]
+}
+
+// size returns the width of the root node.
+func (q *Quadtree) size() int {
+	return 1 << q.Root.Level
+}
+
+// expand doubles the root, keeping the old one in the center.
+func (q *Quadtree) expand() {
+	r := q.Root
+	e := q.Empty(r.Level - 1)
+	q.Root = q.Node(
+		q.Node(e, e, e, r.NW),
+		q.Node(e, e, r.NE, e),
+		q.Node(e, r.SW, e, e),
+		q.Node(r.SE, e, e, e),
+	)
+	half := 1 << (r.Level - 1)
+	q.Origin = q.Origin.Sub(image.Pt(half, half))
+}
+
+func (q *Quadtree) contains(x, y int) bool {
+	return image.Pt(x, y).In(image.Rectangle{q.Origin, q.Origin.Add(image.Pt(q.size(), q.size()))})
+}
+
+func (q *Quadtree) Get(x, y int) bool {
+	if !q.contains(x, y) {
+		return false
+	}
+	return nodeGet(q.Root, x-q.Origin.X, y-q.Origin.Y)
+}
+
+// Set sets the state of a cell, growing the tree if needed.
+func (q *Quadtree) Set(x, y int, alive bool) {
+	for !q.contains(x, y) {
+		if !alive {
+			return
+		}
+		q.expand()
+	}
+	q.Root = q.set(q.Root, x-q.Origin.X, y-q.Origin.Y, alive)
+}
+
+func (q *Quadtree) set(n *Node, x, y int, alive bool) *Node {
+	if n.Level == 0 {
+		return q.Leaf(alive)
+	}
+	half := 1 << (n.Level - 1)
+	switch {
+	case x < half && y < half:
+		return q.Node(q.set(n.NW, x, y, alive), n.NE, n.SW, n.SE)
+	case y < half:
+		return q.Node(n.NW, q.set(n.NE, x-half, y, alive), n.SW, n.SE)
+	case x < half:
+		return q.Node(n.NW, n.NE, q.set(n.SW, x, y-half, alive), n.SE)
+	}
+	return q.Node(n.NW, n.NE, n.SW, q.set(n.SE, x-half, y-half, alive))
+}
+
+func (q *Quadtree) Population() int {
+	return q.Root.Population
+}
+
+// Bounds returns the smallest rectangle holding every live cell.
+func (q *Quadtree) Bounds() image.Rectangle {
+	return bounds(q.Root, make(map[*Node]image.Rectangle)).Add(q.Origin)
+}
+
+// bounds returns the live cells bounding box of n relative to its top-left
+// corner. Shared nodes are only visited once.
+func bounds(n *Node, seen map[*Node]image.Rectangle) image.Rectangle {
+	if n.Population == 0 {
+		return image.Rectangle{}
+	}
+	if n.Level == 0 {
+		return image.Rect(0, 0, 1, 1)
+	}
+	if r, ok := seen[n]; ok {
+		return r
+	}
+	half := 1 << (n.Level - 1)
+	r := bounds(n.NW, seen).
+		Union(bounds(n.NE, seen).Add(image.Pt(half, 0))).
+		Union(bounds(n.SW, seen).Add(image.Pt(0, half))).
+		Union(bounds(n.SE, seen).Add(image.Pt(half, half)))
+	seen[n] = r
+	return r
+}
+
+// Each calls f for every live cell inside r, skipping empty nodes.
+func (q *Quadtree) Each(r image.Rectangle, f func(x, y int)) {
+	q.each(q.Root, q.Origin, r, f)
+}
+
+func (q *Quadtree) each(n *Node, at image.Point, r image.Rectangle, f func(x, y int)) {
+	if n.Population == 0 {
+		return
+	}
+	size := 1 << n.Level
+	if !r.Overlaps(image.Rectangle{at, at.Add(image.Pt(size, size))}) {
+		return
+	}
+	if n.Level == 0 {
+		f(at.X, at.Y)
+		return
+	}
+	half := size / 2
+	q.each(n.NW, at, r, f)
+	q.each(n.NE, at.Add(image.Pt(half, 0)), r, f)
+	q.each(n.SW, at.Add(image.Pt(0, half)), r, f)
+	q.each(n.SE, at.Add(image.Pt(half, half)), r, f)
+}
+
+// QuadtreeOf returns a quadtree with the live cells of w.
+func QuadtreeOf(w World) *Quadtree {
+	q := NewQuadtree()
+	r := w.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if w.Get(x, y) {
+				q.Set(x, y, true)
+			}
+		}
+	}
+	return q
+}
+
+// QuadtreeLoader is implemented by engines that can load a quadtree without
+// going through a dense grid.
+type QuadtreeLoader interface {
+	LoadQuadtree(q *Quadtree, x, y int)
+}
+
+// LoadQuadtree copies the live cells of q into e, with the top-left corner
+// of q's bounds at (x, y). Only the live cells that fall inside e are
+// visited.
+func LoadQuadtree(e Engine, q *Quadtree, x, y int) {
+	if l, ok := e.(QuadtreeLoader); ok {
+		l.LoadQuadtree(q, x, y)
+		return
+	}
+	off := image.Pt(x, y).Sub(q.Bounds().Min)
+	q.Each(e.Bounds().Sub(off), func(cx, cy int) {
+		e.Set(cx+off.X, cy+off.Y, true)
+	})
+}
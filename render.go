@@ -0,0 +1,224 @@
+package gogol
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+)
+
+// PrintTerminal clears the terminal and prints generation i of e, '@' for
+// live cells and ' ' for dead ones. The cells of multi-state worlds are
+// coloured with StatePalette.
+func PrintTerminal(w io.Writer, i int, e World) {
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, "\033[H\033[2J") // Clear terminal and move cursor to top-left
+	fmt.Fprintf(bw, "Iteration: %d\n", i)
+	if sw, ok := multiState(e); ok {
+		writeColors(bw, sw)
+	} else {
+		WriteText(bw, e)
+	}
+	bw.Flush()
+}
+
+// multiState returns e as a StateWorld if it has more than two states.
+func multiState(e World) (StateWorld, bool) {
+	sw, ok := e.(StateWorld)
+	if !ok || sw.StateCount() <= 2 {
+		return nil, false
+	}
+	return sw, true
+}
+
+// View returns the region r of w, which keeps the size of the frames
+// rendered from unbounded engines fixed. The view is a StateWorld if w is.
+func View(w World, r image.Rectangle) World {
+	if sw, ok := w.(StateWorld); ok {
+		return stateView{sw, r}
+	}
+	return view{w, r}
+}
+
+type view struct {
+	World
+	r image.Rectangle
+}
+
+func (v view) Bounds() image.Rectangle { return v.r }
+
+type stateView struct {
+	StateWorld
+	r image.Rectangle
+}
+
+func (v stateView) Bounds() image.Rectangle { return v.r }
+
+// Colored is implemented by worlds with their own colours for the states,
+// such as engines running a rule table with a @COLORS section.
+type Colored interface {
+	Colors() color.Palette
+}
+
+// Recolor returns w drawn with the colours of p, like the states of a
+// continuous world with a colour map.
+func Recolor(w StateWorld, p color.Palette) StateWorld {
+	return recolored{w, p}
+}
+
+type recolored struct {
+	StateWorld
+	p color.Palette
+}
+
+func (r recolored) Colors() color.Palette { return r.p }
+
+// paletteOf returns the colours of the states of e, StatePalette unless e
+// has its own colours for every state.
+func paletteOf(e StateWorld) color.Palette {
+	if c, ok := e.(Colored); ok && len(c.Colors()) >= e.StateCount() {
+		return c.Colors()
+	}
+	return StatePalette(e.StateCount())
+}
+
+// writeColors writes the cells of e as '@' with 24-bit ANSI colours.
+func writeColors(w *bufio.Writer, e StateWorld) {
+	p := paletteOf(e)
+	r := e.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		last := uint8(0)
+		for x := r.Min.X; x < r.Max.X; x++ {
+			s := e.State(x, y)
+			if s == 0 {
+				w.WriteByte(' ')
+				continue
+			}
+			if s != last {
+				r, g, b, _ := p[s].RGBA()
+				fmt.Fprintf(w, "\033[38;2;%d;%d;%dm", r>>8, g>>8, b>>8)
+				last = s
+			}
+			w.WriteByte('@')
+		}
+		w.WriteString("\033[0m\n")
+	}
+}
+
+// StatePalette returns the colours of n cell states: black for dead cells,
+// white for live ones and a fade from orange to dark red for the dying
+// states.
+func StatePalette(n int) color.Palette {
+	p := color.Palette{color.RGBA{0, 0, 0, 255}, color.RGBA{255, 255, 255, 255}}
+	for s := 2; s < n; s++ {
+		t := 0.0
+		if n > 3 {
+			t = float64(s-2) / float64(n-3)
+		}
+		p = append(p, color.RGBA{uint8(255 - 175*t), uint8(160 - 160*t), 0, 255})
+	}
+	return p
+}
+
+// ColorImage returns a snapshot of e coloured with its own colours or
+// StatePalette.
+func ColorImage(e StateWorld) *image.Paletted {
+	r := e.Bounds()
+	img := image.NewPaletted(image.Rect(0, 0, r.Dx(), r.Dy()), paletteOf(e))
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetColorIndex(x-r.Min.X, y-r.Min.Y, e.State(x, y))
+		}
+	}
+	return img
+}
+
+// PrintCells prints generation i of e in plaintext (.cells) format, so the
+// output of a run can be saved as patterns.
+func PrintCells(w io.Writer, i int, e World) {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "!Name: generation %d\n", i)
+	writeRows(bw, e, 'O', '.')
+	bw.Flush()
+}
+
+// WriteText writes e as lines of '@' (alive) and ' ' (dead).
+func WriteText(w io.Writer, e World) error {
+	return writeRows(w, e, '@', ' ')
+}
+
+// ReadText reads a world written by WriteText. Any character other than ' '
+// or '.' is a live cell.
+func ReadText(r io.Reader) (*Grid, error) {
+	var lines []string
+	w := 0
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), "\r")
+		if len(line) > w {
+			w = len(line)
+		}
+		lines = append(lines, line)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if w == 0 || len(lines) == 0 {
+		return nil, fmt.Errorf("gogol: empty text world")
+	}
+	g := NewGrid(w, len(lines))
+	for y, line := range lines {
+		for x := 0; x < len(line); x++ {
+			g.Cells[y][x] = line[x] != ' ' && line[x] != '.'
+		}
+	}
+	return g, nil
+}
+
+// Image returns a grayscale snapshot of e, white for live cells.
+func Image(e World) *image.Gray {
+	r := e.Bounds()
+	img := image.NewGray(image.Rect(0, 0, r.Dx(), r.Dy()))
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if e.Get(x, y) {
+				img.SetGray(x-r.Min.X, y-r.Min.Y, color.Gray{255})
+			}
+		}
+	}
+	return img
+}
+
+// SavePNG writes a snapshot of e to path, in colour for multi-state worlds.
+func SavePNG(path string, e World) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, Frame(e, 1)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadPNG reads an image as a world, every pixel brighter than mid gray is a
+// live cell.
+func ReadPNG(r io.Reader) (*Grid, error) {
+	img, err := png.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	b := img.Bounds()
+	g := NewGrid(b.Dx(), b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			g.Cells[y-b.Min.Y][x-b.Min.X] = color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y >= 128
+		}
+	}
+	return g, nil
+}
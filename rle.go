@@ -0,0 +1,241 @@
+package gogol
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadRLE reads a pattern in Run Length Encoded format. Multi-state
+// patterns, with '.' for dead cells and "A" to "X", "pA" to "yO" for states
+// 1 to 255, are read into a multi-state grid.
+func ReadRLE(r io.Reader) (*Pattern, error) {
+	p := &Pattern{}
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<24)
+
+	// comments and header
+	header := false
+	for !header && s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#"):
+			p.comment(line)
+		default:
+			w, h, rule, err := parseRLEHeader(line)
+			if err != nil {
+				return nil, err
+			}
+			p.Grid = NewGrid(w, h)
+			p.Rule = rule
+			header = true
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if !header {
+		return nil, fmt.Errorf("gogol: rle: missing header")
+	}
+
+	// cells
+	x, y, n := 0, 0, 0
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if strings.HasPrefix(line, "#") {
+			p.comment(line)
+			continue
+		}
+		for i := 0; i < len(line); i++ {
+			c := line[i]
+			switch {
+			case c >= '0' && c <= '9':
+				n = n*10 + int(c-'0')
+				continue
+			case c == ' ' || c == '\t':
+				continue
+			case c == '!':
+				return p, nil
+			}
+			run := n
+			if run == 0 {
+				run = 1
+			}
+			n = 0
+			state := 1
+			switch {
+			case c == '$':
+				x = 0
+				y += run
+				continue
+			case c == 'b' || c == '.':
+				x += run
+				continue
+			case c >= 'A' && c <= 'X':
+				state = int(c-'A') + 1
+			case c >= 'p' && c <= 'y' && i+1 < len(line) && line[i+1] >= 'A' && line[i+1] <= 'X':
+				i++
+				state = 24*int(c-'p'+1) + int(line[i]-'A') + 1
+				if state > 255 {
+					return nil, fmt.Errorf("gogol: rle: invalid state %d", state)
+				}
+			}
+			// 'o' and the other letters are live cells
+			if y >= p.Height || x+run > p.Width {
+				return nil, fmt.Errorf("gogol: rle: cells outside the %dx%d pattern", p.Width, p.Height)
+			}
+			for ; run > 0; run-- {
+				if state == 1 {
+					p.Set(x, y, true)
+				} else {
+					p.SetState(x, y, uint8(state))
+				}
+				x++
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("gogol: rle: missing '!' at the end of the pattern")
+}
+
+// comment stores a #N, #O or #C line.
+func (p *Pattern) comment(line string) {
+	if len(line) < 2 {
+		return
+	}
+	text := strings.TrimSpace(line[2:])
+	switch line[1] {
+	case 'N':
+		p.Name = text
+	case 'O':
+		p.Author = text
+	case 'C', 'c':
+		p.Comments = append(p.Comments, text)
+	}
+}
+
+// parseRLEHeader parses a "x = m, y = n, rule = abc" line.
+func parseRLEHeader(line string) (w, h int, rule string, err error) {
+	w, h = -1, -1
+	for _, f := range strings.Split(line, ",") {
+		k, v, ok := strings.Cut(f, "=")
+		if !ok {
+			return 0, 0, "", fmt.Errorf("gogol: rle: invalid header %q", line)
+		}
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		switch k {
+		case "x":
+			w, err = strconv.Atoi(v)
+		case "y":
+			h, err = strconv.Atoi(v)
+		case "rule":
+			rule = v
+		}
+		if err != nil {
+			return 0, 0, "", fmt.Errorf("gogol: rle: invalid header %q", line)
+		}
+	}
+	if w < 0 || h < 0 {
+		return 0, 0, "", fmt.Errorf("gogol: rle: header %q needs x and y", line)
+	}
+	return w, h, rule, nil
+}
+
+// rleLineLength is the maximum length of the lines of cells written by
+// WriteRLE.
+const rleLineLength = 70
+
+// WriteRLE writes p in Run Length Encoded format, using the multi-state
+// letters if p has a multi-state grid.
+func WriteRLE(w io.Writer, p *Pattern) error {
+	bw := bufio.NewWriter(w)
+	if p.Name != "" {
+		fmt.Fprintf(bw, "#N %s\n", p.Name)
+	}
+	if p.Author != "" {
+		fmt.Fprintf(bw, "#O %s\n", p.Author)
+	}
+	for _, c := range p.Comments {
+		fmt.Fprintf(bw, "#C %s\n", c)
+	}
+	rule := p.Rule
+	if rule == "" {
+		rule = "B3/S23"
+	}
+	fmt.Fprintf(bw, "x = %d, y = %d, rule = %s\n", p.Width, p.Height, rule)
+
+	lw := &lineWrapper{w: bw, max: rleLineLength}
+	rows := 0 // pending end of lines
+	for y := 0; y < p.Height; y++ {
+		end := p.Width
+		for end > 0 && !p.Cells[y][end-1] {
+			end--
+		}
+		if end == 0 {
+			rows++
+			continue
+		}
+		if rows > 0 {
+			lw.run(rows, "$")
+			rows = 0
+		}
+		for x := 0; x < end; {
+			s := p.State(x, y)
+			n := 1
+			for x+n < end && p.State(x+n, y) == s {
+				n++
+			}
+			lw.run(n, rleState(s, p.States != nil))
+			x += n
+		}
+		rows = 1
+	}
+	lw.write("!")
+	bw.WriteString("\n")
+	return bw.Flush()
+}
+
+// rleState returns the tag of a cell state, using the multi-state letters
+// if multi is set.
+func rleState(s uint8, multi bool) string {
+	switch {
+	case !multi && s == 0:
+		return "b"
+	case !multi:
+		return "o"
+	case s == 0:
+		return "."
+	case s <= 24:
+		return string(rune('A' + s - 1))
+	}
+	return string(rune('p'+(s-25)/24)) + string(rune('A'+(s-25)%24))
+}
+
+// lineWrapper writes tokens without splitting them across lines.
+type lineWrapper struct {
+	w   *bufio.Writer
+	max int
+	n   int // length of the current line
+}
+
+func (l *lineWrapper) run(n int, tag string) {
+	if n == 1 {
+		l.write(tag)
+	} else {
+		l.write(strconv.Itoa(n) + tag)
+	}
+}
+
+func (l *lineWrapper) write(tok string) {
+	if l.n+len(tok) > l.max {
+		l.w.WriteString("\n")
+		l.n = 0
+	}
+	l.w.WriteString(tok)
+	l.n += len(tok)
+}
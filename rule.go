@@ -0,0 +1,285 @@
+package gogol
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Rule is a Life-like rule, a lookup table from the number of live
+// neighbours to the next state of dead (Birth) and live (Survive) cells.
+//
+// Isotropic non-totalistic rules also carry a table indexed by the
+// configuration of the neighbours; for them Birth and Survive are set for
+// the counts with at least one configuration.
+//
+// Generations rules have more than two States: live cells that do not
+// survive go through the dying states 2, 3, ... States-1 before they are
+// dead (0) again, and only live cells (1) count as neighbours.
+//
+// Rules can also be given by a RuleTable, be Larger than Life rules or be
+// continuous rules like Lenia, in which case only States is set.
+type Rule struct {
+	Birth      [9]bool
+	Survive    [9]bool
+	States     int           // number of cell states, 2 for Life-like rules
+	hensel     *[2][256]bool // nil for totalistic rules
+	table      *RuleTable    // nil unless the rule is a table
+	ltl        *LtL          // nil unless the rule is Larger than Life
+	continuous *Continuous   // nil unless the cells are continuous
+}
+
+// Life is Conway's Game of Life, B3/S23.
+var Life = MustParseRule("B3/S23")
+
+// namedRules are the rules that can be given by name to ParseRule.
+var namedRules = map[string]string{
+	"life":             "B3/S23",
+	"highlife":         "B36/S23",
+	"seeds":            "B2/S",
+	"daynight":         "B3678/S34678",
+	"dayandnight":      "B3678/S34678",
+	"lifewithoutdeath": "B3/S012345678",
+	"replicator":       "B1357/S1357",
+	"2x2":              "B36/S125",
+	"maze":             "B3/S12345",
+	"briansbrain":      "B2/S/C3",
+	"starwars":         "B2/S345/C4",
+	"bosco":            "R5,C0,M1,S34..58,B34..45,NM",
+	"orbium":           "R=13;T=10;b=1;m=0.15;s=0.015;kn=1;gn=1",
+	"smoothlife":       "ra=21;ri=7;b1=0.278;b2=0.365;d1=0.267;d2=0.445;an=0.028;am=0.147;dt=0.1",
+	"boscosrule":       "R5,C0,M1,S34..58,B34..45,NM",
+	"majority":         "R4,C0,M1,S41..81,B41..81,NM",
+}
+
+// ParseRule parses a rulestring in B/S notation ("B3/S23", "B36S23"), in
+// the legacy S/B notation ("23/3"), in Hensel notation for isotropic
+// non-totalistic rules ("B2ce3ai/S23-k"), a Generations rule in B/S/C or
+// S/B/C notation ("B2/S/C3", "345/2/4"), a Larger than Life rule
+// ("R5,C0,M1,S34..58,B34..45,NM"), a Lenia or SmoothLife rule
+// ("R=13;T=10;b=1;m=0.15;s=0.015;kn=1;gn=1") or a rule name such as
+// "HighLife", "WireWorld" or "Orbium".
+func ParseRule(s string) (Rule, error) {
+	r := Rule{States: 2}
+	s = strings.TrimSpace(s)
+	name := strings.ToLower(strings.NewReplacer(" ", "", "&", "and", "_", "").Replace(s))
+	if t, ok := namedTables[name]; ok {
+		return t.Rule(), nil
+	}
+	if named, ok := namedRules[name]; ok {
+		s = named
+	}
+	if s == "" {
+		return r, fmt.Errorf("gogol: empty rule")
+	}
+	if strings.Contains(s, "=") {
+		return parseContinuous(s)
+	}
+	if (s[0] == 'R' || s[0] == 'r') && strings.Contains(s, ",") {
+		return parseLtL(s)
+	}
+
+	var birth, survive, states string
+	hasStates := false
+	if strings.ContainsAny(s, "BbSs") {
+		// B/S notation, in any order and with an optional slash. 'c' is
+		// also a Hensel letter, so it only starts the states after a slash.
+		var cur *string
+		for i, c := range s {
+			switch {
+			case c == 'B' || c == 'b':
+				cur = &birth
+			case c == 'S' || c == 's':
+				cur = &survive
+			case c == 'C' || c == 'G' || c == 'g' || c == 'c' && i > 0 && s[i-1] == '/':
+				cur = &states
+				hasStates = true
+			case c == '/':
+			case cur != nil:
+				*cur += string(c)
+			default:
+				return r, fmt.Errorf("gogol: invalid rule %q", s)
+			}
+		}
+	} else {
+		// legacy S/B or S/B/C notation
+		parts := strings.Split(s, "/")
+		switch len(parts) {
+		case 3:
+			states, hasStates = parts[2], true
+			fallthrough
+		case 2:
+			survive, birth = parts[0], parts[1]
+		default:
+			return r, fmt.Errorf("gogol: invalid rule %q", s)
+		}
+	}
+	if hasStates {
+		n, err := strconv.Atoi(states)
+		if err != nil || n < 2 || n > 256 {
+			return r, fmt.Errorf("gogol: invalid rule %q: the number of states must be between 2 and 256", s)
+		}
+		r.States = n
+	}
+	var t [2][256]bool
+	if err := parseHensel(birth, &t[0]); err != nil {
+		return r, fmt.Errorf("gogol: invalid rule %q: %v", s, err)
+	}
+	if err := parseHensel(survive, &t[1]); err != nil {
+		return r, fmt.Errorf("gogol: invalid rule %q: %v", s, err)
+	}
+	for cfg := 0; cfg < 256; cfg++ {
+		n := bits.OnesCount8(uint8(cfg))
+		r.Birth[n] = r.Birth[n] || t[0][cfg]
+		r.Survive[n] = r.Survive[n] || t[1][cfg]
+	}
+	// rules listing every letter of their counts are still totalistic
+	for cfg := 0; cfg < 256; cfg++ {
+		n := bits.OnesCount8(uint8(cfg))
+		if t[0][cfg] != r.Birth[n] || t[1][cfg] != r.Survive[n] {
+			r.hensel = &t
+			break
+		}
+	}
+	return r, nil
+}
+
+// MustParseRule is like ParseRule but panics if the rule is invalid.
+func MustParseRule(s string) Rule {
+	r, err := ParseRule(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Totalistic reports whether the rule only depends on the number of live
+// neighbours.
+func (r *Rule) Totalistic() bool {
+	return r.hensel == nil
+}
+
+// Next returns the next state of a cell with n live neighbours, which must
+// be a totalistic rule.
+func (r *Rule) Next(alive bool, n int) bool {
+	if alive {
+		return r.Survive[n]
+	}
+	return r.Birth[n]
+}
+
+// NextConfig returns the next state of a cell with n live neighbours in the
+// configuration cfg. It works for every two-state rule.
+func (r *Rule) NextConfig(alive bool, n int, cfg uint8) bool {
+	if r.hensel != nil {
+		if alive {
+			return r.hensel[1][cfg]
+		}
+		return r.hensel[0][cfg]
+	}
+	return r.Next(alive, n)
+}
+
+// NextState returns the next state of a cell in state s with n live
+// neighbours in the configuration cfg. It works for every rule, including
+// Generations.
+func (r *Rule) NextState(s uint8, n int, cfg uint8) uint8 {
+	switch {
+	case s == 0:
+		if r.NextConfig(false, n, cfg) {
+			return 1
+		}
+		return 0
+	case s == 1 && r.NextConfig(true, n, cfg):
+		return 1
+	case int(s)+1 < r.States:
+		return s + 1 // start or keep dying
+	}
+	return 0
+}
+
+// Generations reports whether the rule has dying states.
+func (r *Rule) Generations() bool {
+	return r.States > 2 && r.table == nil
+}
+
+// Table returns the rule table of the rule, or nil if it is not a table.
+func (r *Rule) Table() *RuleTable {
+	return r.table
+}
+
+// LtL returns the Larger than Life parameters of the rule, or nil if it
+// only looks at the 8 nearest neighbours.
+func (r *Rule) LtL() *LtL {
+	return r.ltl
+}
+
+// Features a rule can need from an engine.
+const (
+	featHensel     = 1 << iota // Hensel notation
+	featStates                 // Generations dying states
+	featLarge                  // Larger than Life
+	featTable                  // RuleTable
+	featContinuous             // Lenia and SmoothLife
+)
+
+// features returns the features needed to run the rule.
+func (r *Rule) features() int {
+	f := 0
+	if r.hensel != nil {
+		f |= featHensel
+	}
+	if r.Generations() {
+		f |= featStates
+	}
+	if r.ltl != nil {
+		f |= featLarge
+	}
+	if r.table != nil {
+		f |= featTable
+	}
+	if r.continuous != nil {
+		f |= featContinuous
+	}
+	return f
+}
+
+// String returns the rule in B/S notation, or in Hensel notation for
+// non-totalistic rules, followed by the number of states for Generations
+// rules. Larger than Life and continuous rules have their own notations.
+func (r Rule) String() string {
+	if r.table != nil {
+		return r.table.Name
+	}
+	if r.ltl != nil {
+		return r.ltl.format(r.States)
+	}
+	if r.continuous != nil {
+		return r.continuous.format()
+	}
+	var sb strings.Builder
+	if r.hensel != nil {
+		sb.WriteByte('B')
+		henselString(&sb, &r.hensel[0])
+		sb.WriteString("/S")
+		henselString(&sb, &r.hensel[1])
+	} else {
+		sb.WriteByte('B')
+		for n, b := range r.Birth {
+			if b {
+				sb.WriteByte('0' + byte(n))
+			}
+		}
+		sb.WriteString("/S")
+		for n, s := range r.Survive {
+			if s {
+				sb.WriteByte('0' + byte(n))
+			}
+		}
+	}
+	if r.Generations() {
+		fmt.Fprintf(&sb, "/C%d", r.States)
+	}
+	return sb.String()
+}
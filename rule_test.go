@@ -0,0 +1,115 @@
+package gogol
+
+import "testing"
+
+// counts returns the neighbour counts set in t, as in a rulestring.
+func counts(t [9]bool) string {
+	s := ""
+	for n, ok := range t {
+		if ok {
+			s += string(rune('0' + n))
+		}
+	}
+	return s
+}
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		in             string
+		birth, survive string
+		states         int
+	}{
+		{"B3/S23", "3", "23", 2},
+		{"b3/s23", "3", "23", 2},
+		{"B3S23", "3", "23", 2},
+		{"S23/B3", "3", "23", 2},
+		{" B3/S23 ", "3", "23", 2},
+		{"23/3", "3", "23", 2},
+		{"/2", "2", "", 2},
+		{"B36/S23", "36", "23", 2},
+		{"B012345678/S012345678", "012345678", "012345678", 2},
+		{"B/S", "", "", 2},
+		{"B2/S/C3", "2", "", 3},
+		{"345/2/4", "2", "345", 4},
+		{"HighLife", "36", "23", 2},
+		{"highlife", "36", "23", 2},
+		{"Seeds", "2", "", 2},
+		{"Day & Night", "3678", "34678", 2},
+		{"DayNight", "3678", "34678", 2},
+		{"Life", "3", "23", 2},
+		{"Brians Brain", "2", "", 3},
+	}
+	for _, tt := range tests {
+		r, err := ParseRule(tt.in)
+		if err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+			continue
+		}
+		if got := counts(r.Birth); got != tt.birth {
+			t.Errorf("%q: birth %q, want %q", tt.in, got, tt.birth)
+		}
+		if got := counts(r.Survive); got != tt.survive {
+			t.Errorf("%q: survival %q, want %q", tt.in, got, tt.survive)
+		}
+		if r.States != tt.states || !r.Totalistic() {
+			t.Errorf("%q: %d states, totalistic %t", tt.in, r.States, r.Totalistic())
+		}
+	}
+}
+
+func TestParseRuleErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"   ",
+		"B9/S23",
+		"B3/S239",
+		"39/3",
+		"23",
+		"3/23/4/5",
+		"B3/S2x",
+		"hello",
+		"B3/S23/C1",
+		"B3/S23/C257",
+		"B3/S23/Cx",
+		"B2q/S23",
+		"B2-/S23",
+		"B3/S23!",
+		"NotARule",
+	} {
+		if r, err := ParseRule(s); err == nil {
+			t.Errorf("%q parsed as %v", s, r)
+		}
+	}
+}
+
+func TestRuleString(t *testing.T) {
+	tests := map[string]string{
+		"B3/S23":                      "B3/S23",
+		"23/3":                        "B3/S23",
+		"S23B3":                       "B3/S23",
+		"Seeds":                       "B2/S",
+		"B/S":                         "B/S",
+		"B2/S/C3":                     "B2/S/C3",
+		"345/2/4":                     "B2/S345/C4",
+		"B2ce3ai/S23-k":               "B2ce3ai/S23-k",
+		"B2-ak3/S2":                   "B2-ak3/S2",
+		"B3aceijknqry/S23":            "B3/S23",
+		"R5,C0,M1,S34..58,B34..45,NM": "R5,C0,M1,S34..58,B34..45,NM",
+	}
+	for in, want := range tests {
+		r := MustParseRule(in)
+		got := r.String()
+		if got != want {
+			t.Errorf("%q: got %q, want %q", in, got, want)
+		}
+		// the string reads back as the same rule
+		again, err := ParseRule(got)
+		if err != nil {
+			t.Errorf("%q: %v", got, err)
+			continue
+		}
+		if again.String() != got || again.Birth != r.Birth || again.Survive != r.Survive || again.States != r.States {
+			t.Errorf("%q reads back as %v", got, again)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package gogol
+
+import (
+	"fmt"
+	"image"
+	"math/rand"
+)
+
+// Symmetry of a random soup, named like in apgsearch.
+type Symmetry string
+
+const (
+	C1 Symmetry = "C1" // no symmetry
+	C2 Symmetry = "C2" // 180 degree rotation
+	C4 Symmetry = "C4" // 90 degree rotation, square regions only
+	D2 Symmetry = "D2" // mirror across the vertical axis
+	D4 Symmetry = "D4" // mirror across both axes
+	D8 Symmetry = "D8" // every rotation and reflection, square regions only
+)
+
+// Soup describes a random initial condition. The same soup always produces
+// the same grid, so runs of different engines can be compared cell by cell.
+type Soup struct {
+	Seed     int64
+	Density  float64         // probability of a cell being alive
+	Region   image.Rectangle // area filled with the soup, the whole grid if empty
+	Symmetry Symmetry        // C1 if empty
+}
+
+// Grid returns the w x h grid of the soup.
+func (s Soup) Grid(w, h int) (*Grid, error) {
+	if !(s.Density >= 0 && s.Density <= 1) {
+		return nil, fmt.Errorf("gogol: soup density must be between 0 and 1, got %g", s.Density)
+	}
+	g := NewGrid(w, h)
+	r := g.Bounds()
+	if !s.Region.Empty() {
+		r = s.Region.Intersect(r)
+	}
+	rw, rh := r.Dx(), r.Dy()
+	sym := s.Symmetry
+	if sym == "" {
+		sym = C1
+	}
+	switch sym {
+	case C1, C2, D2, D4:
+	case C4, D8:
+		if rw != rh {
+			return nil, fmt.Errorf("gogol: %s symmetry needs a square region, got %dx%d", sym, rw, rh)
+		}
+	default:
+		return nil, fmt.Errorf("gogol: unknown symmetry %q", s.Symmetry)
+	}
+
+	// Draw one random value per orbit of the symmetry group, visiting the
+	// cells in row-major order so the result only depends on the seed.
+	rnd := rand.New(rand.NewSource(s.Seed))
+	done := newCells(rw, rh)
+	for v := 0; v < rh; v++ {
+		for u := 0; u < rw; u++ {
+			if done[v][u] {
+				continue
+			}
+			alive := rnd.Float64() < s.Density
+			for _, p := range orbit(sym, u, v, rw, rh) {
+				done[p.Y][p.X] = true
+				g.Cells[r.Min.Y+p.Y][r.Min.X+p.X] = alive
+			}
+		}
+	}
+	return g, nil
+}
+
+// orbit returns the images of (u, v) in a w x h region under sym.
+func orbit(sym Symmetry, u, v, w, h int) []image.Point {
+	p := []image.Point{{u, v}}
+	switch sym {
+	case C2:
+		p = append(p, image.Pt(w-1-u, h-1-v))
+	case C4:
+		p = append(p, image.Pt(w-1-v, u), image.Pt(w-1-u, h-1-v), image.Pt(v, h-1-u))
+	case D2:
+		p = append(p, image.Pt(w-1-u, v))
+	case D4:
+		p = append(p, image.Pt(w-1-u, v), image.Pt(u, h-1-v), image.Pt(w-1-u, h-1-v))
+	case D8:
+		p = append(p,
+			image.Pt(w-1-u, v), image.Pt(u, h-1-v), image.Pt(w-1-u, h-1-v),
+			image.Pt(v, u), image.Pt(w-1-v, u), image.Pt(v, h-1-u), image.Pt(w-1-v, h-1-u))
+	}
+	return p
+}
@@ -0,0 +1,118 @@
+package gogol
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+func TestSoupSeed(t *testing.T) {
+	for _, sym := range []Symmetry{C1, C2, C4, D2, D4, D8} {
+		s := Soup{Seed: 42, Density: 0.5, Symmetry: sym}
+		a, err := s.Grid(32, 32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := s.Grid(32, 32)
+		if !sameCells(a, b) {
+			t.Errorf("%s: the same seed gives different grids", sym)
+		}
+		s.Seed = 43
+		c, _ := s.Grid(32, 32)
+		if sameCells(a, c) {
+			t.Errorf("%s: seeds 42 and 43 give the same grid", sym)
+		}
+	}
+}
+
+func TestSoupDensity(t *testing.T) {
+	for _, density := range []float64{0, 0.1, 0.35, 0.5, 0.9, 1} {
+		g, err := Soup{Seed: 1, Density: density}.Grid(200, 150)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := float64(g.Population()) / (200 * 150)
+		// the standard deviation of the fraction is below 0.003
+		if math.Abs(got-density) > 0.015 {
+			t.Errorf("density %g: %g of the cells alive", density, got)
+		}
+	}
+}
+
+func TestSoupRegion(t *testing.T) {
+	r := image.Rect(3, 2, 9, 7)
+	g, err := Soup{Seed: 1, Density: 1, Region: r}.Grid(12, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 12; x++ {
+			if g.Get(x, y) != image.Pt(x, y).In(r) {
+				t.Fatalf("cell (%d, %d) alive: %t", x, y, g.Get(x, y))
+			}
+		}
+	}
+}
+
+func TestSoupSymmetry(t *testing.T) {
+	// the maps of the symmetry group of each soup on a w x h region
+	type transform func(x, y, w, h int) (int, int)
+	rot180 := func(x, y, w, h int) (int, int) { return w - 1 - x, h - 1 - y }
+	rot90 := func(x, y, w, h int) (int, int) { return w - 1 - y, x }
+	mirrorX := func(x, y, w, h int) (int, int) { return w - 1 - x, y }
+	mirrorY := func(x, y, w, h int) (int, int) { return x, h - 1 - y }
+	diagonal := func(x, y, w, h int) (int, int) { return y, x }
+	tests := []struct {
+		sym  Symmetry
+		w, h int
+		maps []transform
+	}{
+		{C2, 13, 8, []transform{rot180}},
+		{C4, 11, 11, []transform{rot90, rot180}},
+		{C4, 10, 10, []transform{rot90, rot180}},
+		{D2, 9, 6, []transform{mirrorX}},
+		{D4, 9, 6, []transform{mirrorX, mirrorY, rot180}},
+		{D8, 12, 12, []transform{rot90, rot180, mirrorX, mirrorY, diagonal}},
+	}
+	for _, tt := range tests {
+		// offset by the region, so the symmetry is around its centre
+		r := image.Rect(2, 3, 2+tt.w, 3+tt.h)
+		g, err := Soup{Seed: 7, Density: 0.5, Symmetry: tt.sym, Region: r}.Grid(tt.w+5, tt.h+4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, m := range tt.maps {
+			for y := 0; y < tt.h; y++ {
+				for x := 0; x < tt.w; x++ {
+					u, v := m(x, y, tt.w, tt.h)
+					if g.Get(r.Min.X+x, r.Min.Y+y) != g.Get(r.Min.X+u, r.Min.Y+v) {
+						t.Fatalf("%s %dx%d: map %d sends (%d, %d) to (%d, %d) of another state", tt.sym, tt.w, tt.h, i, x, y, u, v)
+					}
+				}
+			}
+		}
+		// and it is not simply empty or full
+		if n := g.Population(); n == 0 || n == tt.w*tt.h {
+			t.Errorf("%s: %d cells alive", tt.sym, n)
+		}
+	}
+}
+
+func TestSoupErrors(t *testing.T) {
+	for _, s := range []Soup{
+		{Density: -0.1},
+		{Density: 1.5},
+		{Density: math.NaN()},
+		{Density: 0.5, Symmetry: "C3"},
+		{Density: 0.5, Symmetry: C4},
+		{Density: 0.5, Symmetry: D8, Region: image.Rect(5, 0, 10, 5)}, // clipped to 3x5
+	} {
+		if _, err := s.Grid(8, 6); err == nil {
+			t.Errorf("%+v: no error", s)
+		}
+	}
+	// a square region makes C4 and D8 soups possible in any grid
+	if _, err := (Soup{Density: 0.5, Symmetry: C4, Region: image.Rect(1, 0, 7, 6)}).Grid(8, 6); err != nil {
+		t.Error(err)
+	}
+}
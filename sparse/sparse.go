@@ -0,0 +1,263 @@
+// Package sparse stores integer sparse matrices in several layouts and
+// multiplies them by vectors. CSR is the one built from dense matrices and
+// stencils; COO, ELL and DIA are converted from it to compare how each layout
+// suits a given matrix.
+package sparse
+
+import (
+	"image"
+	"slices"
+	"sync"
+)
+
+// Matrix is a sparse matrix in any of the layouts.
+type Matrix interface {
+	// Dims returns the number of rows and columns.
+	Dims() (rows, cols int)
+	// NNZ returns the number of stored entries.
+	NNZ() int
+	// MulVecRows sets y[i] to row i of the matrix times x for the rows from
+	// start to end.
+	MulVecRows(x, y []int, start, end int)
+}
+
+// MulVec sets y to m times x.
+func MulVec(m Matrix, x, y []int) {
+	rows, _ := m.Dims()
+	m.MulVecRows(x, y, 0, rows)
+}
+
+// MulVecParallel sets y to m times x, splitting the rows between workers
+// goroutines.
+func MulVecParallel(m Matrix, x, y []int, workers int) {
+	rows, _ := m.Dims()
+	workers = max(min(workers, rows), 1)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start, end := w*rows/workers, (w+1)*rows/workers
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.MulVecRows(x, y, start, end)
+		}()
+	}
+	wg.Wait()
+}
+
+// CSR is a matrix in compressed sparse row format: the entries of row i are
+// at RowPtr[i] to RowPtr[i+1] of ColIdx and Values.
+type CSR struct {
+	Rows   int
+	Cols   int
+	RowPtr []int
+	ColIdx []int
+	Values []int
+}
+
+// FromDense returns the non-zero entries of a dense matrix in CSR format.
+func FromDense(matrix [][]int) *CSR {
+	m := &CSR{Rows: len(matrix), RowPtr: make([]int, len(matrix)+1)}
+	for i := range matrix {
+		m.Cols = max(m.Cols, len(matrix[i]))
+		m.RowPtr[i] = len(m.ColIdx)
+		for j, v := range matrix[i] {
+			if v != 0 {
+				m.ColIdx = append(m.ColIdx, j)
+				m.Values = append(m.Values, v)
+			}
+		}
+	}
+	m.RowPtr[len(matrix)] = len(m.ColIdx)
+	return m
+}
+
+// FromStencil returns the matrix that sums the cells at the offsets of
+// stencil for every cell of a w x h grid stored row by row, cell (x, y) being
+// row and column y*w+x. at returns the column of the neighbour q of cell p,
+// where q may be outside the grid, or false to leave it out.
+func FromStencil(w, h int, stencil []image.Point, at func(p, q image.Point) (int, bool)) *CSR {
+	m := &CSR{Rows: w * h, Cols: w * h, RowPtr: make([]int, w*h+1)}
+	for i := 0; i < w*h; i++ {
+		m.RowPtr[i] = len(m.ColIdx)
+		p := image.Pt(i%w, i/w)
+		for _, d := range stencil {
+			if j, ok := at(p, p.Add(d)); ok {
+				m.ColIdx = append(m.ColIdx, j)
+				m.Values = append(m.Values, 1)
+			}
+		}
+	}
+	m.RowPtr[w*h] = len(m.ColIdx)
+	return m
+}
+
+func (m *CSR) Dims() (int, int) {
+	return m.Rows, m.Cols
+}
+
+func (m *CSR) NNZ() int {
+	return len(m.Values)
+}
+
+func (m *CSR) MulVecRows(x, y []int, start, end int) {
+	for i := start; i < end; i++ {
+		sum := 0
+		for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+			sum += m.Values[k] * x[m.ColIdx[k]]
+		}
+		y[i] = sum
+	}
+}
+
+// Dense returns the matrix as a dense one.
+func (m *CSR) Dense() [][]int {
+	out := make([][]int, m.Rows)
+	for i := range out {
+		out[i] = make([]int, m.Cols)
+		for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+			out[i][m.ColIdx[k]] += m.Values[k]
+		}
+	}
+	return out
+}
+
+// COO returns the matrix in coordinate format, which shares no memory with m.
+func (m *CSR) COO() *COO {
+	c := &COO{Rows: m.Rows, Cols: m.Cols, Row: make([]int, m.NNZ()), Col: slices.Clone(m.ColIdx), Values: slices.Clone(m.Values)}
+	for i := 0; i < m.Rows; i++ {
+		for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+			c.Row[k] = i
+		}
+	}
+	return c
+}
+
+// ELL returns the matrix in ELLPACK format.
+func (m *CSR) ELL() *ELL {
+	e := &ELL{Rows: m.Rows, Cols: m.Cols}
+	for i := 0; i < m.Rows; i++ {
+		e.Width = max(e.Width, m.RowPtr[i+1]-m.RowPtr[i])
+	}
+	e.ColIdx = make([]int, m.Rows*e.Width)
+	e.Values = make([]int, m.Rows*e.Width)
+	for i := 0; i < m.Rows; i++ {
+		copy(e.ColIdx[i*e.Width:], m.ColIdx[m.RowPtr[i]:m.RowPtr[i+1]])
+		copy(e.Values[i*e.Width:], m.Values[m.RowPtr[i]:m.RowPtr[i+1]])
+	}
+	return e
+}
+
+// DIA returns the matrix in diagonal format.
+func (m *CSR) DIA() *DIA {
+	d := &DIA{Rows: m.Rows, Cols: m.Cols}
+	index := map[int]int{} // diagonal of each offset
+	for i := 0; i < m.Rows; i++ {
+		for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+			off := m.ColIdx[k] - i
+			n, ok := index[off]
+			if !ok {
+				n = len(d.Offsets)
+				index[off] = n
+				d.Offsets = append(d.Offsets, off)
+				d.Values = append(d.Values, make([]int, m.Rows))
+			}
+			d.Values[n][i] += m.Values[k]
+		}
+	}
+	return d
+}
+
+// COO is a matrix in coordinate format: entry k is Values[k] at row Row[k]
+// and column Col[k], sorted by row.
+type COO struct {
+	Rows   int
+	Cols   int
+	Row    []int
+	Col    []int
+	Values []int
+}
+
+func (m *COO) Dims() (int, int) {
+	return m.Rows, m.Cols
+}
+
+func (m *COO) NNZ() int {
+	return len(m.Values)
+}
+
+func (m *COO) MulVecRows(x, y []int, start, end int) {
+	clear(y[start:end])
+	// Find the first entry of the rows
+	lo, hi := 0, len(m.Row)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if m.Row[mid] < start {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	for k := lo; k < len(m.Row) && m.Row[k] < end; k++ {
+		y[m.Row[k]] += m.Values[k] * x[m.Col[k]]
+	}
+}
+
+// ELL is a matrix in ELLPACK format: every row stores Width entries, the
+// ones of row i at i*Width of ColIdx and Values, padded with zeros.
+type ELL struct {
+	Rows   int
+	Cols   int
+	Width  int
+	ColIdx []int
+	Values []int
+}
+
+func (m *ELL) Dims() (int, int) {
+	return m.Rows, m.Cols
+}
+
+// NNZ returns the number of stored entries, padding included.
+func (m *ELL) NNZ() int {
+	return len(m.Values)
+}
+
+func (m *ELL) MulVecRows(x, y []int, start, end int) {
+	for i := start; i < end; i++ {
+		sum := 0
+		for k := i * m.Width; k < (i+1)*m.Width; k++ {
+			sum += m.Values[k] * x[m.ColIdx[k]]
+		}
+		y[i] = sum
+	}
+}
+
+// DIA is a matrix in diagonal format: Values[n][i] is the entry at row i
+// and column i+Offsets[n].
+type DIA struct {
+	Rows    int
+	Cols    int
+	Offsets []int
+	Values  [][]int
+}
+
+func (m *DIA) Dims() (int, int) {
+	return m.Rows, m.Cols
+}
+
+// NNZ returns the number of stored entries, the zeros of every diagonal
+// included.
+func (m *DIA) NNZ() int {
+	return len(m.Offsets) * m.Rows
+}
+
+func (m *DIA) MulVecRows(x, y []int, start, end int) {
+	clear(y[start:end])
+	for n, off := range m.Offsets {
+		// Rows whose entry on this diagonal is inside the matrix
+		lo, hi := max(start, -off), min(end, m.Cols-off)
+		values := m.Values[n]
+		for i := lo; i < hi; i++ {
+			y[i] += values[i] * x[i+off]
+		}
+	}
+}
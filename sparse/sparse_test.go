@@ -0,0 +1,163 @@
+package sparse
+
+import (
+	"image"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func randomDense(rows, cols int, density float64, r *rand.Rand) [][]int {
+	m := make([][]int, rows)
+	for i := range m {
+		m[i] = make([]int, cols)
+		for j := range m[i] {
+			if r.Float64() < density {
+				m[i][j] = r.IntN(9) - 4
+			}
+		}
+	}
+	return m
+}
+
+func mulDense(m [][]int, x []int) []int {
+	y := make([]int, len(m))
+	for i := range m {
+		for j, v := range m[i] {
+			y[i] += v * x[j]
+		}
+	}
+	return y
+}
+
+func TestFromDense(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, size := range [][2]int{{0, 0}, {1, 1}, {3, 7}, {7, 3}, {20, 20}} {
+		d := randomDense(size[0], size[1], 0.3, r)
+		m := FromDense(d)
+		if rows, cols := m.Dims(); rows != size[0] || cols != size[1] {
+			t.Errorf("%v: dims %dx%d", size, rows, cols)
+		}
+		nnz := 0
+		for _, row := range d {
+			for _, v := range row {
+				if v != 0 {
+					nnz++
+				}
+			}
+		}
+		if m.NNZ() != nnz {
+			t.Errorf("%v: %d entries, want %d", size, m.NNZ(), nnz)
+		}
+		got := m.Dense()
+		for i := range d {
+			if !slices.Equal(got[i], d[i]) {
+				t.Fatalf("%v: row %d is %v, want %v", size, i, got[i], d[i])
+			}
+		}
+	}
+}
+
+func TestFromStencil(t *testing.T) {
+	// the von Neumann neighbours on a 4x3 torus
+	w, h := 4, 3
+	stencil := []image.Point{{0, -1}, {-1, 0}, {1, 0}, {0, 1}}
+	m := FromStencil(w, h, stencil, func(p, q image.Point) (int, bool) {
+		return (q.Y+h)%h*w + (q.X+w)%w, true
+	})
+	if m.NNZ() != 4*w*h {
+		t.Fatalf("%d entries", m.NNZ())
+	}
+	d := m.Dense()
+	// cell (0, 0) sums (0, 2), (3, 0), (1, 0) and (0, 1)
+	for _, j := range []int{8, 3, 1, 4} {
+		if d[0][j] != 1 {
+			t.Errorf("row 0, column %d is %d", j, d[0][j])
+		}
+	}
+	// on a 3x3 torus the vertical neighbours of a 1 cell tall grid are the
+	// cell itself, counted twice
+	m = FromStencil(3, 1, stencil, func(p, q image.Point) (int, bool) {
+		return (q.X + 3) % 3, true
+	})
+	if d := m.Dense(); d[1][1] != 2 || d[1][0] != 1 || d[1][2] != 1 {
+		t.Errorf("row 1 is %v", d[1])
+	}
+	// neighbours beyond the edge are left out
+	m = FromStencil(w, h, stencil, func(p, q image.Point) (int, bool) {
+		return q.Y*w + q.X, q.In(image.Rect(0, 0, w, h))
+	})
+	if n := m.RowPtr[1] - m.RowPtr[0]; n != 2 {
+		t.Errorf("corner has %d neighbours", n)
+	}
+}
+
+func TestLayouts(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	for _, size := range [][2]int{{1, 1}, {5, 9}, {9, 5}, {31, 31}} {
+		d := randomDense(size[0], size[1], 0.2, r)
+		// an empty row at each end
+		clear(d[0])
+		clear(d[len(d)-1])
+		m := FromDense(d)
+		x := make([]int, size[1])
+		for i := range x {
+			x[i] = r.IntN(21) - 10
+		}
+		want := mulDense(d, x)
+		for _, layout := range []Matrix{m, m.COO(), m.ELL(), m.DIA()} {
+			y := make([]int, size[0])
+			for i := range y {
+				y[i] = 99 // every row must be overwritten
+			}
+			MulVec(layout, x, y)
+			if !slices.Equal(y, want) {
+				t.Errorf("%T %v: got %v, want %v", layout, size, y, want)
+			}
+			for _, workers := range []int{1, 2, 3, 7, 100} {
+				p := make([]int, size[0])
+				MulVecParallel(layout, x, p, workers)
+				if !slices.Equal(p, want) {
+					t.Errorf("%T %v, %d workers: got %v, want %v", layout, size, workers, p, want)
+				}
+			}
+			// a range of rows leaves the others alone
+			if size[0] > 2 {
+				y := make([]int, size[0])
+				layout.MulVecRows(x, y, 1, size[0]-1)
+				if y[0] != 0 || y[size[0]-1] != 0 || !slices.Equal(y[1:size[0]-1], want[1:size[0]-1]) {
+					t.Errorf("%T %v: rows 1 to %d are %v, want %v", layout, size, size[0]-1, y, want)
+				}
+			}
+		}
+	}
+}
+
+func TestCOOCopies(t *testing.T) {
+	m := FromDense([][]int{{1, 0, 2}, {0, 3, 0}})
+	c := m.COO()
+	c.Col[0], c.Values[0] = 2, 7
+	if m.ColIdx[0] != 0 || m.Values[0] != 1 {
+		t.Errorf("changing the COO matrix changed the CSR one: %v %v", m.ColIdx, m.Values)
+	}
+	if !slices.Equal(c.Row, []int{0, 0, 1}) {
+		t.Errorf("rows %v", c.Row)
+	}
+}
+
+func TestDIADiagonals(t *testing.T) {
+	// a tridiagonal matrix has three diagonals of length Rows
+	m := FromDense([][]int{
+		{2, 1, 0, 0},
+		{1, 2, 1, 0},
+		{0, 1, 2, 1},
+		{0, 0, 1, 2},
+	})
+	d := m.DIA()
+	if len(d.Offsets) != 3 || d.NNZ() != 12 {
+		t.Errorf("offsets %v, %d entries", d.Offsets, d.NNZ())
+	}
+	if e := m.ELL(); e.Width != 3 || e.NNZ() != 12 {
+		t.Errorf("ELL width %d, %d entries", e.Width, e.NNZ())
+	}
+}
@@ -0,0 +1,442 @@
+package gogol
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// RuleTable is a multi-state rule given by a list of transitions, as in the
+// @TABLE section of Golly .rule files. Each transition lists the states of
+// the cell and its neighbours, in Golly's order (C, N, NE, E, SE, S, SW, W,
+// NW for Moore, C, N, E, S, W for von Neumann) and the next state of the
+// cell. The first matching transition is used and cells that match none
+// keep their state.
+type RuleTable struct {
+	Name         string
+	States       int
+	Neighborhood string        // "Moore" or "vonNeumann"
+	Symmetries   string        // "none", "rotate4", "permute", ...
+	Colors       color.Palette // colours of the states from @COLORS, if any
+
+	transitions []transition
+	permute     bool
+}
+
+// stateSet is a set of cell states.
+type stateSet [4]uint64
+
+func (s *stateSet) add(v uint8) {
+	s[v/64] |= 1 << (v % 64)
+}
+
+func (s *stateSet) has(v uint8) bool {
+	return s[v/64]&(1<<(v%64)) != 0
+}
+
+// Rule returns a rule that runs the table.
+func (t *RuleTable) Rule() Rule {
+	return Rule{States: t.States, table: t}
+}
+
+// size returns the number of states before the output of a transition, the
+// cell and its neighbours.
+func (t *RuleTable) size() int {
+	return neighbourCount[t.Neighborhood] + 1
+}
+
+func (t *RuleTable) valid(v int) bool {
+	return v >= 0 && v < t.States
+}
+
+// transition is a compiled line of a rule table, with the variables
+// replaced by the sets of states they stand for.
+type transition struct {
+	in  []stateSet // cell and neighbours
+	out uint8
+}
+
+var neighbourCount = map[string]int{"Moore": 8, "vonNeumann": 4}
+
+// symmetryPerms are the permutations of the neighbours of each symmetry,
+// "permute" is matched separately.
+var symmetryPerms = map[string]map[string][][]int{
+	"Moore": {
+		"none":               {rotate(8, 0)},
+		"rotate4":            rotations(8, 2),
+		"rotate8":            rotations(8, 1),
+		"reflect_horizontal": {rotate(8, 0), reflect(8)},
+		"rotate4reflect":     withReflections(rotations(8, 2)),
+		"rotate8reflect":     withReflections(rotations(8, 1)),
+		"permute":            {rotate(8, 0)},
+	},
+	"vonNeumann": {
+		"none":               {rotate(4, 0)},
+		"rotate4":            rotations(4, 1),
+		"reflect_horizontal": {rotate(4, 0), reflect(4)},
+		"rotate4reflect":     withReflections(rotations(4, 1)),
+		"permute":            {rotate(4, 0)},
+	},
+}
+
+// rotate returns the permutation of n neighbours listed clockwise that turns
+// them by k positions.
+func rotate(n, k int) []int {
+	p := make([]int, n)
+	for i := range p {
+		p[i] = (i + k) % n
+	}
+	return p
+}
+
+func rotations(n, step int) [][]int {
+	var ps [][]int
+	for k := 0; k < n; k += step {
+		ps = append(ps, rotate(n, k))
+	}
+	return ps
+}
+
+// reflect flips n neighbours listed clockwise from north left to right.
+func reflect(n int) []int {
+	p := make([]int, n)
+	for i := range p {
+		p[i] = (n - i) % n
+	}
+	return p
+}
+
+func withReflections(ps [][]int) [][]int {
+	out := append([][]int{}, ps...)
+	r := reflect(len(ps[0]))
+	for _, p := range ps {
+		q := make([]int, len(p))
+		for i := range p {
+			q[i] = p[r[i]]
+		}
+		out = append(out, q)
+	}
+	return out
+}
+
+// WireWorld is Brian Silverman's WireWorld: electron heads (1) become tails
+// (2), tails become conductors (3) and conductors become heads when one or
+// two of their neighbours are heads.
+var WireWorld = mustReadRuleTable(`@RULE WireWorld
+@TABLE
+n_states:4
+neighborhood:Moore
+symmetries:permute
+var a={0,1,2,3}
+var b={a}
+var c={a}
+var d={a}
+var e={a}
+var f={a}
+var g={a}
+var h={a}
+var o={0,2,3}
+var p={o}
+var q={o}
+var r={o}
+var s={o}
+var t={o}
+var u={o}
+1,a,b,c,d,e,f,g,h,2
+2,a,b,c,d,e,f,g,h,3
+3,1,o,p,q,r,s,t,u,1
+3,1,1,o,p,q,r,s,t,1
+@COLORS
+0 48 48 48
+1 0 128 255
+2 255 255 255
+3 255 128 0
+`)
+
+// namedTables are the rule tables that can be given by name to ParseRule.
+var namedTables = map[string]*RuleTable{
+	"wireworld": WireWorld,
+}
+
+func mustReadRuleTable(s string) *RuleTable {
+	t, err := ReadRuleTable(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// ReadRuleTable reads a rule in Golly .rule format, or a bare @TABLE
+// section as in the older .table files. Only the @RULE, @TABLE and @COLORS
+// sections are used.
+func ReadRuleTable(r io.Reader) (*RuleTable, error) {
+	t := &RuleTable{Name: "table", States: 2, Neighborhood: "Moore", Symmetries: "none"}
+	vars := map[string][]uint8{}
+	var lines [][]string // transitions, compiled once the header is read
+	section := "@TABLE"
+	table := false
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		if strings.HasPrefix(line, "@") {
+			f := strings.Fields(line)
+			section = f[0]
+			if section == "@RULE" && len(f) > 1 {
+				t.Name = f[1]
+			}
+			continue
+		}
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		switch section {
+		case "@TABLE":
+			table = true
+			if err := t.parseLine(line, vars, &lines); err != nil {
+				return nil, fmt.Errorf("gogol: rule table %s: %v", t.Name, err)
+			}
+		case "@COLORS":
+			if err := t.parseColor(line); err != nil {
+				return nil, fmt.Errorf("gogol: rule table %s: %v", t.Name, err)
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if !table {
+		return nil, fmt.Errorf("gogol: rule table %s: missing @TABLE section", t.Name)
+	}
+	for _, l := range lines {
+		if err := t.compile(l, vars); err != nil {
+			return nil, fmt.Errorf("gogol: rule table %s: %v", t.Name, err)
+		}
+	}
+	return t, nil
+}
+
+// parseLine reads a header, variable or transition line of a @TABLE.
+func (t *RuleTable) parseLine(line string, vars map[string][]uint8, lines *[][]string) error {
+	if k, v, ok := strings.Cut(line, ":"); ok {
+		v = strings.TrimSpace(v)
+		switch strings.TrimSpace(k) {
+		case "n_states":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 2 || n > 256 {
+				return fmt.Errorf("invalid number of states %q", v)
+			}
+			t.States = n
+		case "neighborhood":
+			if _, ok := neighbourCount[v]; !ok {
+				return fmt.Errorf("unsupported neighborhood %q", v)
+			}
+			t.Neighborhood = v
+		case "symmetries":
+			t.Symmetries = v
+			t.permute = v == "permute"
+		default:
+			return fmt.Errorf("unknown setting %q", k)
+		}
+		return nil
+	}
+	if strings.HasPrefix(line, "var ") {
+		name, set, ok := strings.Cut(line[4:], "=")
+		name, set = strings.TrimSpace(name), strings.TrimSpace(set)
+		if !ok || !strings.HasPrefix(set, "{") || !strings.HasSuffix(set, "}") {
+			return fmt.Errorf("invalid variable %q", line)
+		}
+		var values []uint8
+		for _, f := range strings.Split(set[1:len(set)-1], ",") {
+			f = strings.TrimSpace(f)
+			if vs, ok := vars[f]; ok {
+				values = append(values, vs...)
+				continue
+			}
+			v, err := strconv.Atoi(f)
+			if err != nil || !t.valid(v) {
+				return fmt.Errorf("invalid state %q in variable %s", f, name)
+			}
+			values = append(values, uint8(v))
+		}
+		vars[name] = values
+		return nil
+	}
+	var tokens []string
+	if strings.Contains(line, ",") {
+		for _, f := range strings.Split(line, ",") {
+			tokens = append(tokens, strings.TrimSpace(f))
+		}
+	} else {
+		// compact form, one digit per state
+		for _, c := range strings.ReplaceAll(line, " ", "") {
+			tokens = append(tokens, string(c))
+		}
+	}
+	*lines = append(*lines, tokens)
+	return nil
+}
+
+// parseColor reads a "state R G B" line of a @COLORS section.
+func (t *RuleTable) parseColor(line string) error {
+	f := strings.Fields(line)
+	var v [4]int
+	if len(f) != 4 {
+		return fmt.Errorf("invalid colour %q", line)
+	}
+	for i := range v {
+		n, err := strconv.Atoi(f[i])
+		if err != nil || n < 0 || n > 255 {
+			return fmt.Errorf("invalid colour %q", line)
+		}
+		v[i] = n
+	}
+	for len(t.Colors) <= v[0] {
+		t.Colors = append(t.Colors, color.RGBA{0, 0, 0, 255})
+	}
+	t.Colors[v[0]] = color.RGBA{uint8(v[1]), uint8(v[2]), uint8(v[3]), 255}
+	return nil
+}
+
+// compile adds the transitions of a line. Variables used more than once
+// are bound, they take the same value everywhere, so the line is expanded
+// for each of their values. Then a transition is added for each symmetry.
+func (t *RuleTable) compile(tokens []string, vars map[string][]uint8) error {
+	if len(tokens) != t.size()+1 {
+		return fmt.Errorf("transition %q needs %d states", strings.Join(tokens, ","), t.size()+1)
+	}
+	uses := map[string]int{}
+	var bound []string
+	for _, tok := range tokens {
+		if _, ok := vars[tok]; ok {
+			if uses[tok]++; uses[tok] == 2 {
+				bound = append(bound, tok)
+			}
+			continue
+		}
+		if v, err := strconv.Atoi(tok); err != nil || !t.valid(v) {
+			return fmt.Errorf("invalid state %q", tok)
+		}
+	}
+	if out := tokens[len(tokens)-1]; uses[out] == 1 {
+		return fmt.Errorf("output variable %s is not bound", out)
+	}
+	perms := symmetryPerms[t.Neighborhood][t.Symmetries]
+	if perms == nil {
+		return fmt.Errorf("unsupported symmetries %q for %s neighborhood", t.Symmetries, t.Neighborhood)
+	}
+
+	value := map[string]uint8{}
+	var expand func(k int)
+	expand = func(k int) {
+		if k < len(bound) {
+			for _, v := range vars[bound[k]] {
+				value[bound[k]] = v
+				expand(k + 1)
+			}
+			return
+		}
+		in := make([]stateSet, t.size())
+		for i := range in {
+			in[i] = t.set(tokens[i], vars, value)
+		}
+		out := tokens[len(tokens)-1]
+		var o uint8
+		if v, ok := value[out]; ok {
+			o = v
+		} else {
+			n, _ := strconv.Atoi(out)
+			o = uint8(n)
+		}
+		for _, p := range perms {
+			tr := transition{in: make([]stateSet, len(in)), out: o}
+			tr.in[0] = in[0]
+			for i, j := range p {
+				tr.in[1+i] = in[1+j]
+			}
+			t.transitions = append(t.transitions, tr)
+		}
+	}
+	expand(0)
+	return nil
+}
+
+// set returns the states a token stands for.
+func (t *RuleTable) set(tok string, vars map[string][]uint8, value map[string]uint8) stateSet {
+	if v, ok := value[tok]; ok {
+		var s stateSet
+		s.add(v)
+		return s
+	}
+	if vs, ok := vars[tok]; ok {
+		var s stateSet
+		for _, v := range vs {
+			s.add(v)
+		}
+		return s
+	}
+	n, _ := strconv.Atoi(tok)
+	var s stateSet
+	s.add(uint8(n))
+	return s
+}
+
+// Next returns the next state of a cell from the states of the cell and its
+// neighbours, in Golly's order.
+func (t *RuleTable) Next(nb []uint8) uint8 {
+	for i := range t.transitions {
+		tr := &t.transitions[i]
+		if !tr.in[0].has(nb[0]) {
+			continue
+		}
+		if t.permute && matchPermuted(tr.in[1:], nb[1:]) || !t.permute && matchAll(tr.in[1:], nb[1:]) {
+			return tr.out
+		}
+	}
+	return nb[0]
+}
+
+func matchAll(in []stateSet, nb []uint8) bool {
+	for i, s := range nb {
+		if !in[i].has(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchPermuted reports whether the neighbours can be assigned to the sets
+// in some order, finding a perfect matching with augmenting paths.
+func matchPermuted(in []stateSet, nb []uint8) bool {
+	var owner [8]int // neighbour matched to each set, -1 if none
+	for i := range in {
+		owner[i] = -1
+	}
+	var try func(n int, seen *[8]bool) bool
+	try = func(n int, seen *[8]bool) bool {
+		for i := range in {
+			if seen[i] || !in[i].has(nb[n]) {
+				continue
+			}
+			seen[i] = true
+			if owner[i] < 0 || try(owner[i], seen) {
+				owner[i] = n
+				return true
+			}
+		}
+		return false
+	}
+	for n := range nb {
+		var seen [8]bool
+		if !try(n, &seen) {
+			return false
+		}
+	}
+	return true
+}
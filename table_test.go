@@ -0,0 +1,321 @@
+package gogol
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func readTable(t *testing.T, s string) *RuleTable {
+	t.Helper()
+	rt, err := ReadRuleTable(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rt
+}
+
+// neighbours returns the states of the neighbours of the transitions of a
+// table without variables, as strings of digits in Golly's order.
+func neighbours(rt *RuleTable) []string {
+	var out []string
+	for _, tr := range rt.transitions {
+		var sb strings.Builder
+		for _, s := range tr.in[1:] {
+			for v := 0; v < rt.States; v++ {
+				if s.has(uint8(v)) {
+					sb.WriteByte('0' + byte(v))
+				}
+			}
+		}
+		out = append(out, sb.String())
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestReadRuleTable(t *testing.T) {
+	rt := readTable(t, `@RULE Test
+@TABLE
+# comments and blank lines are skipped
+
+n_states: 3
+neighborhood: vonNeumann
+var a={0,1}
+var b={a,2} # variables of variables
+0,1,0,0,0,1
+1,b,0,0,0,2
+20000 0 # compact form
+@COLORS
+1 255 0 0
+2 0 0 255
+`)
+	if rt.Name != "Test" || rt.States != 3 || rt.Neighborhood != "vonNeumann" || rt.Symmetries != "none" {
+		t.Errorf("got %s with %d states, %s, %s", rt.Name, rt.States, rt.Neighborhood, rt.Symmetries)
+	}
+	wantColors := color.Palette{color.RGBA{0, 0, 0, 255}, color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}}
+	if !slices.Equal(rt.Colors, wantColors) {
+		t.Errorf("colours %v, want %v", rt.Colors, wantColors)
+	}
+	tests := []struct {
+		nb   []uint8 // C, N, E, S, W
+		want uint8
+	}{
+		{[]uint8{0, 1, 0, 0, 0}, 1},
+		{[]uint8{0, 0, 1, 0, 0}, 0}, // no symmetries
+		{[]uint8{1, 2, 0, 0, 0}, 2},
+		{[]uint8{1, 2, 0, 0, 1}, 1}, // no transition, the cell keeps its state
+		{[]uint8{2, 0, 0, 0, 0}, 0},
+		{[]uint8{2, 1, 0, 0, 0}, 2},
+	}
+	for _, tt := range tests {
+		if got := rt.Next(tt.nb); got != tt.want {
+			t.Errorf("Next(%v) = %d, want %d", tt.nb, got, tt.want)
+		}
+	}
+
+	// old .table files have no headers
+	rt = readTable(t, "n_states:2\n0,1,1,1,1,1,1,1,1,1\n")
+	if rt.Name != "table" || rt.Neighborhood != "Moore" || len(rt.transitions) != 1 {
+		t.Errorf("got %s, %s, %d transitions", rt.Name, rt.Neighborhood, len(rt.transitions))
+	}
+}
+
+func TestRuleTableBoundVariables(t *testing.T) {
+	rt := readTable(t, `n_states:3
+neighborhood:vonNeumann
+var a={0,1,2}
+var b={a}
+var c={a}
+# a is bound: the cell becomes the state of N if E has the same one
+0,a,a,b,c,a
+`)
+	if len(rt.transitions) != 3 {
+		t.Errorf("%d transitions, want one for each value of a", len(rt.transitions))
+	}
+	tests := []struct {
+		nb   []uint8
+		want uint8
+	}{
+		{[]uint8{0, 1, 1, 0, 2}, 1},
+		{[]uint8{0, 2, 2, 1, 1}, 2},
+		{[]uint8{0, 1, 2, 0, 0}, 0}, // N and E differ
+		{[]uint8{0, 2, 1, 2, 2}, 0},
+	}
+	for _, tt := range tests {
+		if got := rt.Next(tt.nb); got != tt.want {
+			t.Errorf("Next(%v) = %d, want %d", tt.nb, got, tt.want)
+		}
+	}
+}
+
+func TestRuleTableSymmetries(t *testing.T) {
+	// N is 1 and NE or E is 2, and every rotation or reflection of them
+	tests := []struct {
+		neighborhood, symmetries string
+		want                     []string
+	}{
+		{"Moore", "none", []string{"12000000"}},
+		{"Moore", "rotate4", []string{
+			"00000012", "00001200", "00120000", "12000000"}},
+		{"Moore", "rotate8", []string{
+			"00000012", "00000120", "00001200", "00012000",
+			"00120000", "01200000", "12000000", "20000001"}},
+		{"Moore", "reflect_horizontal", []string{"10000002", "12000000"}},
+		{"Moore", "rotate4reflect", []string{
+			"00000012", "00000210", "00001200", "00021000",
+			"00120000", "02100000", "10000002", "12000000"}},
+		{"Moore", "rotate8reflect", []string{
+			"00000012", "00000021", "00000120", "00000210",
+			"00001200", "00002100", "00012000", "00021000",
+			"00120000", "00210000", "01200000", "02100000",
+			"10000002", "12000000", "20000001", "21000000"}},
+		{"vonNeumann", "none", []string{"1200"}},
+		{"vonNeumann", "rotate4", []string{"0012", "0120", "1200", "2001"}},
+		{"vonNeumann", "reflect_horizontal", []string{"1002", "1200"}},
+		{"vonNeumann", "rotate4reflect", []string{
+			"0012", "0021", "0120", "0210", "1002", "1200", "2001", "2100"}},
+	}
+	for _, tt := range tests {
+		line := "0,1,2,0,0,0,0,0,0,1"
+		if tt.neighborhood == "vonNeumann" {
+			line = "0,1,2,0,0,1"
+		}
+		rt := readTable(t, "n_states:3\nneighborhood:"+tt.neighborhood+"\nsymmetries:"+tt.symmetries+"\n"+line+"\n")
+		if got := neighbours(rt); !slices.Equal(got, tt.want) {
+			t.Errorf("%s %s: got %v, want %v", tt.neighborhood, tt.symmetries, got, tt.want)
+		}
+	}
+
+	// permute matches the neighbours in any order with a single transition
+	rt := readTable(t, "n_states:3\nsymmetries:permute\n0,1,2,0,0,0,0,0,0,1\n")
+	if len(rt.transitions) != 1 {
+		t.Errorf("permute: %d transitions", len(rt.transitions))
+	}
+	for _, nb := range [][]uint8{
+		{0, 1, 2, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 2, 0, 0, 1, 0},
+		{0, 2, 0, 0, 0, 0, 0, 0, 1},
+	} {
+		if rt.Next(nb) != 1 {
+			t.Errorf("permute: %v does not match", nb)
+		}
+	}
+	if nb := []uint8{0, 1, 1, 0, 0, 0, 0, 0, 0}; rt.Next(nb) != 0 {
+		t.Errorf("permute: %v matches", nb)
+	}
+}
+
+func TestRuleTableErrors(t *testing.T) {
+	for _, s := range []string{
+		"@RULE Empty\n",
+		"n_states:1\n",
+		"n_states:257\n",
+		"n_states:x\n",
+		"neighborhood:hexagonal\n",
+		"symmetries:rotate3\n0,1,0,0,0,0,0,0,0,1\n",
+		"neighborhood:vonNeumann\nsymmetries:rotate8\n0,1,0,0,0,1\n",
+		"colours:3\n",
+		"var a=0,1\n",
+		"var a={0,2}\n",
+		"var a={0,b}\n",
+		"0,1,0,0,0,0,0,0,1\n",     // too short
+		"0,1,0,0,0,0,0,0,0,0,1\n", // too long
+		"0,2,0,0,0,0,0,0,0,1\n",   // state out of range
+		"0,x,0,0,0,0,0,0,0,1\n",   // unknown variable
+		"var a={0,1}\nvar b={0,1}\n0,a,0,0,0,0,0,0,0,b\n", // output variable not bound
+		"n_states:3\n@COLORS\n1 255 0\n",
+		"n_states:3\n@COLORS\n1 255 0 256\n",
+	} {
+		if rt, err := ReadRuleTable(strings.NewReader(s)); err == nil {
+			t.Errorf("%q read with %d transitions", s, len(rt.transitions))
+		}
+	}
+}
+
+// wireworld returns a table engine running WireWorld on the cells drawn
+// with '.' for empty cells, '#' for conductors, '@' for electron heads and
+// '~' for their tails.
+func wireworld(rows ...string) *TableEngine {
+	r := MustParseRule("WireWorld")
+	e := NewTableEngine(Config{Width: len(rows[0]), Height: len(rows), Rule: &r})
+	for y, row := range rows {
+		for x, c := range row {
+			e.SetState(x, y, uint8(strings.IndexRune(".@~#", c)))
+		}
+	}
+	return e
+}
+
+func wireRows(e *TableEngine) []string {
+	rows := make([]string, e.h)
+	for y := range rows {
+		for x := 0; x < e.w; x++ {
+			rows[y] += string(".@~#"[e.State(x, y)])
+		}
+	}
+	return rows
+}
+
+func TestWireWorldWire(t *testing.T) {
+	e := wireworld("........", "~@######", "........")
+	for _, want := range []string{"#~@#####", "##~@####", "###~@###", "####~@##"} {
+		e.Step()
+		if got := wireRows(e); got[1] != want || got[0] != "........" || got[2] != "........" {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestWireWorldDiode(t *testing.T) {
+	// Electrons pass from left to right. Coming from the right, they reach
+	// the cell after the gap together with the cells above and below it, and
+	// the next cell, seeing three heads, stays a conductor
+	diode := []string{"....##....", "#####.####", "....##...."}
+	e := wireworld("....##....", "~@###.####", "....##....")
+	e.StepN(8)
+	if got, want := wireRows(e), []string{"....##....", "#####.##~@", "....##...."}; !slices.Equal(got, want) {
+		t.Errorf("from the left: got %q, want %q", got, want)
+	}
+	e.StepN(2)
+	if got := wireRows(e); !slices.Equal(got, diode) {
+		t.Errorf("from the left: got %q after the electron left", got)
+	}
+
+	e = wireworld("....##....", "#####.##@~", "....##....")
+	for gen := 1; gen <= 10; gen++ {
+		e.Step()
+		if e.State(0, 1) == 1 || e.State(1, 1) == 1 || e.State(2, 1) == 1 {
+			t.Fatalf("from the right: the electron passed in generation %d: %q", gen, wireRows(e))
+		}
+	}
+	if got := wireRows(e); !slices.Equal(got, diode) {
+		t.Errorf("from the right: got %q", got)
+	}
+}
+
+func TestRuleTableFile(t *testing.T) {
+	// a signal (1) running east on a track (3), leaving a trail (2)
+	const file = `@RULE Signal
+
+A signal running east.
+
+@TABLE
+n_states:4
+neighborhood:vonNeumann
+symmetries:none
+var a={0,1,2,3}
+var b={a}
+var c={a}
+var d={a}
+3,a,b,c,1,1
+1,a,b,c,d,2
+2,a,b,c,d,3
+
+@COLORS
+1 255 255 0
+2 255 0 0
+3 0 0 255
+
+@ICONS
+XPM
+`
+	path := filepath.Join(t.TempDir(), "Signal.rule")
+	if err := os.WriteFile(path, []byte(file), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rt, err := ReadRuleTable(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rt.Rule()
+	if r.String() != "Signal" || r.States != 4 || r.Generations() || len(rt.Colors) != 4 {
+		t.Fatalf("got %v with %d states and %d colours", r, r.States, len(rt.Colors))
+	}
+	e, err := New("table", Config{Width: 6, Height: 1, Rule: &r})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sw := e.(StateWorld)
+	for x, s := range []uint8{2, 1, 3, 3, 3, 3} {
+		sw.(MultiState).SetState(x, 0, s)
+	}
+	for _, want := range []string{"321333", "332133", "333213"} {
+		e.Step()
+		var got strings.Builder
+		for x := 0; x < 6; x++ {
+			got.WriteByte('0' + sw.State(x, 0))
+		}
+		if got.String() != want {
+			t.Fatalf("got %s, want %s", got.String(), want)
+		}
+	}
+}
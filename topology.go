@@ -0,0 +1,89 @@
+package gogol
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Topology decides what lies beyond the edges of a bounded world.
+type Topology string
+
+const (
+	Plane        Topology = "plane"    // the cells outside are dead
+	Torus        Topology = "torus"    // opposite edges are joined
+	Cylinder     Topology = "cylinder" // left and right edges are joined, top and bottom are dead
+	Klein        Topology = "klein"    // like a torus, but the top and bottom edges are joined flipped
+	CrossSurface Topology = "cross"    // both pairs of edges are joined flipped
+)
+
+// ParseTopology parses a topology name, the empty string is the plane.
+func ParseTopology(s string) (Topology, error) {
+	switch t := Topology(strings.ToLower(strings.TrimSpace(s))); t {
+	case "":
+		return Plane, nil
+	case Plane, Torus, Cylinder, Klein, CrossSurface:
+		return t, nil
+	}
+	return "", fmt.Errorf("gogol: unknown topology %q", s)
+}
+
+// wrap returns the cell of a w x h world found at (x, y), which may be
+// outside it, and false if it is a dead cell beyond an open edge. Each
+// crossing of a flipped edge mirrors the other coordinate, so on a cross
+// surface the corners meet in a single point as in the projective plane.
+func (t Topology) wrap(x, y, w, h int) (int, int, bool) {
+	if x >= 0 && x < w && y >= 0 && y < h {
+		return x, y, true
+	}
+	switch t {
+	case Torus, Klein, CrossSurface:
+		kx, ky := floorDiv(x, w), floorDiv(y, h)
+		x, y = x-kx*w, y-ky*h
+		if ky%2 != 0 && t != Torus {
+			x = w - 1 - x
+		}
+		if kx%2 != 0 && t == CrossSurface {
+			y = h - 1 - y
+		}
+		return x, y, true
+	case Cylinder:
+		if y < 0 || y >= h {
+			return 0, 0, false
+		}
+		return x - floorDiv(x, w)*w, y, true
+	}
+	return 0, 0, false
+}
+
+// floorDiv divides rounding towards minus infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+// fillHalo copies into the padding of a world padded with pad cells on each
+// side the cells it stands for in topology t. The padding of the plane is
+// left dead.
+func fillHalo[T any](cells [][]T, pad int, t Topology) {
+	if t == Plane || t == "" {
+		return
+	}
+	var dead T
+	h, w := len(cells)-2*pad, len(cells[0])-2*pad
+	for i, row := range cells {
+		for j := 0; j < len(row); j++ {
+			if i >= pad && i < h+pad && j >= pad && j < w+pad {
+				j = w + pad - 1 // skip the interior of the row
+				continue
+			}
+			if x, y, ok := t.wrap(j-pad, i-pad, w, h); ok {
+				row[j] = cells[y+pad][x+pad]
+			} else {
+				row[j] = dead
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package gogol
+
+import "testing"
+
+func TestWrap(t *testing.T) {
+	// in a 4x3 world
+	type cell struct {
+		x, y int
+		ok   bool
+	}
+	tests := []struct {
+		topo Topology
+		x, y int
+		want cell
+	}{
+		{Plane, 2, 1, cell{2, 1, true}},
+		{Plane, -1, 0, cell{}},
+		{Plane, 4, 2, cell{}},
+		{Plane, 0, -1, cell{}},
+		{Plane, 3, 3, cell{}},
+		{Plane, -1, -1, cell{}},
+		{"", 4, 0, cell{}},
+
+		{Torus, 0, 0, cell{0, 0, true}},
+		{Torus, -1, 0, cell{3, 0, true}},
+		{Torus, 4, 2, cell{0, 2, true}},
+		{Torus, 1, -1, cell{1, 2, true}},
+		{Torus, 2, 3, cell{2, 0, true}},
+		{Torus, -1, -1, cell{3, 2, true}},
+		{Torus, 4, 3, cell{0, 0, true}},
+		{Torus, -5, 0, cell{3, 0, true}},
+		{Torus, 9, 7, cell{1, 1, true}},
+
+		{Cylinder, -1, 1, cell{3, 1, true}},
+		{Cylinder, 4, 0, cell{0, 0, true}},
+		{Cylinder, 8, 2, cell{0, 2, true}},
+		{Cylinder, 1, -1, cell{}},
+		{Cylinder, 2, 3, cell{}},
+		{Cylinder, -1, -1, cell{}},
+		{Cylinder, 4, 3, cell{}},
+
+		// crossing the top or bottom edge flips x
+		{Klein, -1, 1, cell{3, 1, true}},
+		{Klein, 4, 2, cell{0, 2, true}},
+		{Klein, 1, -1, cell{2, 2, true}},
+		{Klein, 0, 3, cell{3, 0, true}},
+		{Klein, 3, 3, cell{0, 0, true}},
+		{Klein, 1, 6, cell{1, 0, true}},
+		{Klein, -1, -1, cell{0, 2, true}},
+		{Klein, 4, 3, cell{3, 0, true}},
+
+		// and crossing the left or right edge also flips y, so each corner
+		// outside meets the nearest corner inside
+		{CrossSurface, -1, 0, cell{3, 2, true}},
+		{CrossSurface, 4, 2, cell{0, 0, true}},
+		{CrossSurface, 4, 1, cell{0, 1, true}},
+		{CrossSurface, 1, -1, cell{2, 2, true}},
+		{CrossSurface, 0, 3, cell{3, 0, true}},
+		{CrossSurface, -1, -1, cell{0, 0, true}},
+		{CrossSurface, 4, -1, cell{3, 0, true}},
+		{CrossSurface, -1, 3, cell{0, 2, true}},
+		{CrossSurface, 4, 3, cell{3, 2, true}},
+		{CrossSurface, 8, 6, cell{0, 0, true}},
+	}
+	for _, tt := range tests {
+		x, y, ok := tt.topo.wrap(tt.x, tt.y, 4, 3)
+		if got := (cell{x, y, ok}); got != tt.want {
+			t.Errorf("%s: (%d, %d) wraps to %v, want %v", tt.topo, tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestParseTopology(t *testing.T) {
+	for s, want := range map[string]Topology{"": Plane, "Torus": Torus, " klein ": Klein, "cross": CrossSurface} {
+		if got, err := ParseTopology(s); err != nil || got != want {
+			t.Errorf("%q: got %q, %v", s, got, err)
+		}
+	}
+	if _, err := ParseTopology("sphere"); err == nil {
+		t.Error("sphere parsed")
+	}
+}
@@ -0,0 +1,119 @@
+package gogol
+
+import (
+	"fmt"
+	"image"
+	"io"
+)
+
+// Reference returns the engine the others are checked against for rule r:
+// naive, or for rules it cannot run the simplest engine that can.
+func Reference(r Rule) string {
+	switch {
+	case r.Table() != nil:
+		return "table"
+	case r.Continuous() != nil:
+		return "lenia"
+	case r.LtL() != nil:
+		return "ltl"
+	case r.Generations():
+		return "generations"
+	}
+	return "naive"
+}
+
+// Divergence is the first cell where an engine disagrees with the reference.
+type Divergence struct {
+	Engine     string
+	Reference  string
+	Generation int // 0 is the initial state
+	X          int
+	Y          int
+	// States of the cell in the reference and in the engine, 0 for dead and
+	// 1 for alive unless both have more states.
+	Want uint8
+	Got  uint8
+}
+
+func (d *Divergence) Error() string {
+	return fmt.Sprintf("gogol: engine %s diverges from %s at generation %d, cell (%d, %d): want state %d, got %d",
+		d.Engine, d.Reference, d.Generation, d.X, d.Y, d.Want, d.Got)
+}
+
+// stateAt returns the state of the cell at (x, y) of w, or whether it is
+// alive if states is false.
+func stateAt(w World, x, y int, states bool) uint8 {
+	if states {
+		return w.(StateWorld).State(x, y)
+	}
+	if w.Get(x, y) {
+		return 1
+	}
+	return 0
+}
+
+// Verify runs the named engines and the reference engine of the rule of c
+// from g for gens generations, comparing every cell after every generation.
+// It returns the first divergence found, or nil if all the engines agree.
+// The states of the cells are compared when both engines have them, so
+// dying states, rule table states and continuous values must match too.
+// Unbounded engines are checked against a reference grown by gens cells on
+// each side, which patterns cannot reach in gens generations.
+func Verify(names []string, c Config, g *Grid, gens int) (*Divergence, error) {
+	reference := Reference(c.rule())
+	ref, err := New(reference, c)
+	if err != nil {
+		return nil, err
+	}
+	Load(ref, g)
+	var wide Engine
+	engines := make([]Engine, len(names))
+	for i, name := range names {
+		if engines[i], err = New(name, c); err != nil {
+			return nil, err
+		}
+		Load(engines[i], g)
+		if c, ok := engines[i].(io.Closer); ok {
+			defer c.Close()
+		}
+		if _, ok := engines[i].(Unbounded); ok && wide == nil {
+			wc := c
+			wc.Width, wc.Height = c.Width+2*gens, c.Height+2*gens
+			if wide, err = New(reference, wc); err != nil {
+				return nil, err
+			}
+			LoadAt(wide, g, gens, gens)
+		}
+	}
+
+	for gen := 0; gen <= gens; gen++ {
+		if gen > 0 {
+			ref.Step()
+			if wide != nil {
+				wide.Step()
+			}
+			for _, e := range engines {
+				e.Step()
+			}
+		}
+		for i, e := range engines {
+			want, off := ref, image.Point{}
+			if _, ok := e.(Unbounded); ok {
+				want, off = wide, image.Pt(gens, gens)
+			}
+			_, wantStates := want.(StateWorld)
+			_, gotStates := e.(StateWorld)
+			states := wantStates && gotStates
+			r := want.Bounds()
+			for y := r.Min.Y; y < r.Max.Y; y++ {
+				for x := r.Min.X; x < r.Max.X; x++ {
+					w, g := stateAt(want, x, y, states), stateAt(e, x-off.X, y-off.Y, states)
+					if w != g {
+						return &Divergence{Engine: names[i], Reference: reference, Generation: gen, X: x - off.X, Y: y - off.Y, Want: w, Got: g}, nil
+					}
+				}
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,168 @@
+package gogol
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+var topologies = []Topology{Plane, Torus, Cylinder, Klein, CrossSurface}
+
+// verifySizes are odd and prime sizes, each also run one cell taller and two
+// cells wider to catch engines mixing up rows and columns.
+var verifySizes = []int{1, 2, 3, 5, 7, 13, 17}
+
+// verifyWorkers do not divide most of the sizes.
+var verifyWorkers = []int{1, 2, 3, 7}
+
+// supported returns the registered engines that can run c, and which of
+// them split the work between workers.
+func supported(c Config) (names, concurrent []string) {
+	for _, name := range Engines() {
+		e, err := New(name, c)
+		if err != nil {
+			continue
+		}
+		names = append(names, name)
+		if _, ok := e.(Concurrent); ok {
+			concurrent = append(concurrent, name)
+		}
+		if cl, ok := e.(io.Closer); ok {
+			cl.Close()
+		}
+	}
+	return names, concurrent
+}
+
+// verifyAll checks every engine that can run rule against the reference on
+// every topology, size and number of workers.
+func verifyAll(t *testing.T, rule string, gens int) {
+	r := MustParseRule(rule)
+	for _, topo := range topologies {
+		t.Run(string(topo), func(t *testing.T) {
+			names, concurrent := supported(Config{Width: 1, Height: 1, Rule: &r, Topology: topo})
+			if len(names) == 0 {
+				t.Skipf("no engine runs %v on the %s", r, topo)
+			}
+			for _, n := range verifySizes {
+				for _, size := range [][2]int{{n, n}, {n, n + 1}, {n + 2, n}} {
+					g, err := Soup{Seed: int64(n), Density: 0.35}.Grid(size[0], size[1])
+					if err != nil {
+						t.Fatal(err)
+					}
+					for i, w := range verifyWorkers {
+						engines := names
+						if i > 0 {
+							engines = concurrent
+						}
+						c := Config{Width: size[0], Height: size[1], Workers: w, Rule: &r, Topology: topo}
+						d, err := Verify(engines, c, g, gens)
+						if err != nil {
+							t.Fatalf("%dx%d, %d workers: %v", size[0], size[1], w, err)
+						}
+						if d != nil {
+							t.Fatalf("%dx%d, %d workers: %v", size[0], size[1], w, d)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		rule string
+		gens int
+	}{
+		{"B3/S23", 30},
+		{"B36/S23", 30},
+		{"B2/S", 10},
+		{"B2ce3ai/S23-k", 20},
+		{"B2/S/C3", 20},
+		{"345/2/4", 20},
+		{"WireWorld", 10},
+		{"R5,C0,M1,S34..58,B34..45,NM", 10},
+		{"R2,C0,M0,S2..4,B3..3,NN", 10},
+		{"R3,C0,M0,S8..13,B9..11,NC", 10},
+		{"R2,C4,M1,S3..5,B3..4,NM", 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			verifyAll(t, tt.rule, tt.gens)
+		})
+	}
+}
+
+func TestVerifyDivergence(t *testing.T) {
+	// A blinker on an engine that is not stepped diverges at generation 1
+	g := NewGrid(5, 5)
+	for x := 1; x <= 3; x++ {
+		g.Set(x, 2, true)
+	}
+	name := "frozen"
+	Register(name, func(c Config) (Engine, error) {
+		return &frozen{NewNaive(c)}, nil
+	})
+	defer delete(factories, name)
+	d, err := Verify([]string{name}, Config{Width: 5, Height: 5}, g, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d == nil || d.Engine != name || d.Generation != 1 || d.Want == d.Got {
+		t.Fatalf("got divergence %v, want one of %s at generation 1", d, name)
+	}
+}
+
+// frozen is an engine whose cells never change.
+type frozen struct {
+	*Naive
+}
+
+func (f *frozen) Step()       {}
+func (f *frozen) StepN(n int) {}
+
+func TestVerifyDyingState(t *testing.T) {
+	// Star Wars cells die through states 2 and 3, both alive to Get, so only
+	// the states tell an engine reporting 3 for 2 from the reference
+	r := MustParseRule("345/2/4")
+	g, err := Soup{Seed: 3, Density: 0.5}.Grid(12, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := "aged"
+	Register(name, func(c Config) (Engine, error) {
+		return &aged{NewGenerations(c)}, nil
+	})
+	defer delete(factories, name)
+	d, err := Verify([]string{name}, Config{Width: 12, Height: 12, Rule: &r}, g, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d == nil || d.Engine != name || d.Reference != "generations" || d.Want != 2 || d.Got != 3 {
+		t.Fatalf("got divergence %v, want state 2 reported as 3", d)
+	}
+}
+
+// aged is an engine that reports the first dying state as the second one.
+type aged struct {
+	*Generations
+}
+
+func (a *aged) State(x, y int) uint8 {
+	if s := a.Generations.State(x, y); s != 2 {
+		return s
+	}
+	return 3
+}
+
+func TestVerifyUnsupported(t *testing.T) {
+	r := MustParseRule("B3/S23")
+	_, err := Verify([]string{"sparse"}, Config{Width: 4, Height: 4, Rule: &r, Topology: Torus}, NewGrid(4, 4), 1)
+	if err == nil {
+		t.Fatal("sparse ran on the torus")
+	}
+	if got := fmt.Sprint(err); got != "gogol: engine sparse only runs on the plane, got torus" {
+		t.Errorf("got error %q", got)
+	}
+}
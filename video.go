@@ -0,0 +1,282 @@
+package gogol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+)
+
+// Frame returns e as an image with scale x scale pixels per cell. Boards are
+// used as they are, since they already implement image.Image, and
+// multi-state worlds are drawn with ColorImage.
+func Frame(e World, scale int) image.Image {
+	var img image.Image
+	if b, ok := e.(*Board); ok {
+		img = b
+	} else if sw, ok := multiState(e); ok {
+		img = ColorImage(sw)
+	} else {
+		img = Image(e)
+	}
+	if scale <= 1 {
+		return img
+	}
+	return &scaled{img, scale}
+}
+
+// scaled magnifies an image by an integer factor.
+type scaled struct {
+	image.Image
+	s int
+}
+
+func (s *scaled) Bounds() image.Rectangle {
+	r := s.Image.Bounds()
+	return image.Rect(0, 0, r.Dx()*s.s, r.Dy()*s.s)
+}
+
+func (s *scaled) At(x, y int) color.Color {
+	r := s.Image.Bounds()
+	return s.Image.At(r.Min.X+x/s.s, r.Min.Y+y/s.s)
+}
+
+// Y4MWriter writes frames as an uncompressed YUV4MPEG2 stream with full
+// resolution chroma (4:4:4), which any encoder can read from a pipe. The
+// colours are converted with the full range JFIF coefficients, as the
+// XCOLORRANGE=FULL tag of the header tells players.
+type Y4MWriter struct {
+	w    *bufio.Writer
+	fps  int
+	size image.Point // set by the first frame
+	buf  []byte
+}
+
+// NewY4MWriter returns a Y4MWriter that writes to w at fps frames per
+// second.
+func NewY4MWriter(w io.Writer, fps int) *Y4MWriter {
+	if fps < 1 {
+		fps = 1
+	}
+	return &Y4MWriter{w: bufio.NewWriter(w), fps: fps}
+}
+
+// WriteFrame appends img to the stream. Every frame must have the size of
+// the first one.
+func (y *Y4MWriter) WriteFrame(img image.Image) error {
+	r := img.Bounds()
+	if y.size == (image.Point{}) {
+		y.size = r.Size()
+		if _, err := fmt.Fprintf(y.w, "YUV4MPEG2 W%d H%d F%d:1 Ip A1:1 C444 XCOLORRANGE=FULL\n", y.size.X, y.size.Y, y.fps); err != nil {
+			return err
+		}
+		y.buf = make([]byte, 3*y.size.X*y.size.Y)
+	}
+	if r.Size() != y.size {
+		return fmt.Errorf("gogol: frame size %v does not match stream size %v", r.Size(), y.size)
+	}
+	n := y.size.X * y.size.Y
+	i := 0
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			c := color.YCbCrModel.Convert(img.At(px, py)).(color.YCbCr)
+			y.buf[i] = c.Y
+			y.buf[n+i] = c.Cb
+			y.buf[2*n+i] = c.Cr
+			i++
+		}
+	}
+	if _, err := y.w.WriteString("FRAME\n"); err != nil {
+		return err
+	}
+	_, err := y.w.Write(y.buf)
+	return err
+}
+
+// Close flushes the stream. It does not close the underlying writer.
+func (y *Y4MWriter) Close() error {
+	return y.w.Flush()
+}
+
+// AVIWriter writes frames as JPEG images in an AVI container (Motion JPEG).
+// The header is rewritten on Close with the final frame count, so the
+// output must be seekable.
+type AVIWriter struct {
+	w       io.WriteSeeker
+	fps     int
+	quality int
+	size    image.Point // set by the first frame
+	index   []aviChunk
+	movi    int // bytes written to the movi list after its fourcc
+	maxSize int // largest frame, for the suggested buffer size
+	buf     bytes.Buffer
+}
+
+type aviChunk struct {
+	offset int // relative to the movi fourcc
+	size   int
+}
+
+// aviHeaderSize is the size of everything before the first frame chunk.
+const aviHeaderSize = 224
+
+// NewAVIWriter returns an AVIWriter that writes to w at fps frames per
+// second, encoding frames with the given JPEG quality (1 to 100).
+func NewAVIWriter(w io.WriteSeeker, fps, quality int) *AVIWriter {
+	if fps < 1 {
+		fps = 1
+	}
+	if quality < 1 || quality > 100 {
+		quality = jpeg.DefaultQuality
+	}
+	return &AVIWriter{w: w, fps: fps, quality: quality, movi: 4}
+}
+
+// WriteFrame appends img to the video. Every frame must have the size of the
+// first one.
+func (a *AVIWriter) WriteFrame(img image.Image) error {
+	r := img.Bounds()
+	if a.size == (image.Point{}) {
+		a.size = r.Size()
+		// placeholder, the real header is written by Close
+		if _, err := a.w.Write(a.header()); err != nil {
+			return err
+		}
+	}
+	if r.Size() != a.size {
+		return fmt.Errorf("gogol: frame size %v does not match video size %v", r.Size(), a.size)
+	}
+
+	a.buf.Reset()
+	if err := jpeg.Encode(&a.buf, img, &jpeg.Options{Quality: a.quality}); err != nil {
+		return err
+	}
+	size := a.buf.Len()
+	a.index = append(a.index, aviChunk{offset: a.movi, size: size})
+	if size > a.maxSize {
+		a.maxSize = size
+	}
+	if size%2 == 1 {
+		a.buf.WriteByte(0) // chunks are word aligned
+	}
+	chunk := make([]byte, 8, 8+a.buf.Len())
+	copy(chunk, "00dc")
+	binary.LittleEndian.PutUint32(chunk[4:], uint32(size))
+	chunk = append(chunk, a.buf.Bytes()...)
+	if _, err := a.w.Write(chunk); err != nil {
+		return err
+	}
+	a.movi += len(chunk)
+	return nil
+}
+
+// Close writes the index and the final header. It does not close the
+// underlying writer.
+func (a *AVIWriter) Close() error {
+	if len(a.index) == 0 {
+		return fmt.Errorf("gogol: no frames to encode")
+	}
+	idx := make([]byte, 8+16*len(a.index))
+	copy(idx, "idx1")
+	binary.LittleEndian.PutUint32(idx[4:], uint32(16*len(a.index)))
+	for i, c := range a.index {
+		e := idx[8+16*i:]
+		copy(e, "00dc")
+		binary.LittleEndian.PutUint32(e[4:], 0x10) // AVIIF_KEYFRAME
+		binary.LittleEndian.PutUint32(e[8:], uint32(c.offset))
+		binary.LittleEndian.PutUint32(e[12:], uint32(c.size))
+	}
+	if _, err := a.w.Write(idx); err != nil {
+		return err
+	}
+	if _, err := a.w.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := a.w.Write(a.header()); err != nil {
+		return err
+	}
+	_, err := a.w.Seek(0, io.SeekEnd)
+	return err
+}
+
+// header returns the RIFF header, the stream headers and the start of the
+// movi list for the frames written so far.
+func (a *AVIWriter) header() []byte {
+	var h bytes.Buffer
+	u32 := func(v int) { binary.Write(&h, binary.LittleEndian, uint32(v)) }
+	u16 := func(v int) { binary.Write(&h, binary.LittleEndian, uint16(v)) }
+	frames := len(a.index)
+	w, hh := a.size.X, a.size.Y
+
+	h.WriteString("RIFF")
+	u32(aviHeaderSize - 8 + a.movi - 4 + 8 + 16*frames)
+	h.WriteString("AVI ")
+
+	h.WriteString("LIST")
+	u32(192)
+	h.WriteString("hdrl")
+
+	// main header
+	h.WriteString("avih")
+	u32(56)
+	u32(1000000 / a.fps) // microseconds per frame
+	u32(a.maxSize * a.fps)
+	u32(0)    // padding granularity
+	u32(0x10) // AVIF_HASINDEX
+	u32(frames)
+	u32(0) // initial frames
+	u32(1) // streams
+	u32(a.maxSize)
+	u32(w)
+	u32(hh)
+	u32(0)
+	u32(0)
+	u32(0)
+	u32(0)
+
+	// video stream
+	h.WriteString("LIST")
+	u32(116)
+	h.WriteString("strl")
+	h.WriteString("strh")
+	u32(56)
+	h.WriteString("vids")
+	h.WriteString("MJPG")
+	u32(0) // flags
+	u16(0) // priority
+	u16(0) // language
+	u32(0) // initial frames
+	u32(1) // scale
+	u32(a.fps)
+	u32(0) // start
+	u32(frames)
+	u32(a.maxSize)
+	u32(-1) // default quality
+	u32(0)  // sample size
+	u16(0)
+	u16(0)
+	u16(w)
+	u16(hh)
+	h.WriteString("strf")
+	u32(40)
+	u32(40) // BITMAPINFOHEADER size
+	u32(w)
+	u32(hh)
+	u16(1)  // planes
+	u16(24) // bits per pixel
+	h.WriteString("MJPG")
+	u32(w * hh * 3)
+	u32(0)
+	u32(0)
+	u32(0)
+	u32(0)
+
+	h.WriteString("LIST")
+	u32(a.movi)
+	h.WriteString("movi")
+	return h.Bytes()
+}
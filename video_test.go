@@ -0,0 +1,128 @@
+package gogol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/jpeg"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestY4MWriter(t *testing.T) {
+	g := NewGrid(2, 1)
+	g.Set(1, 0, true)
+	var buf bytes.Buffer
+	y := NewY4MWriter(&buf, 25)
+	if err := y.WriteFrame(Frame(g, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := y.WriteFrame(Frame(g, 2)); err == nil {
+		t.Error("wrote a frame of another size")
+	}
+	if err := y.Close(); err != nil {
+		t.Fatal(err)
+	}
+	header, data, _ := strings.Cut(buf.String(), "\n")
+	if want := "YUV4MPEG2 W2 H1 F25:1 Ip A1:1 C444 XCOLORRANGE=FULL"; header != want {
+		t.Errorf("got header %q, want %q", header, want)
+	}
+	// Full range: black and white are 0 and 255, with neutral chroma
+	if want := "FRAME\n\x00\xff\x80\x80\x80\x80"; data != want {
+		t.Errorf("got frame %q, want %q", data, want)
+	}
+}
+
+func TestAVIWriter(t *testing.T) {
+	const frames = 5
+	var f seekBuffer
+	a := NewAVIWriter(&f, 10, 90)
+	g := NewGrid(8, 8)
+	for i := 0; i < frames; i++ {
+		g.Set(i, i, true)
+		if err := a.WriteFrame(Frame(g, 2)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	b := f.buf
+	if string(b[:4]) != "RIFF" || string(b[8:12]) != "AVI " {
+		t.Fatalf("not an AVI file: %q", b[:12])
+	}
+	if size := int(binary.LittleEndian.Uint32(b[4:])); size != len(b)-8 {
+		t.Errorf("RIFF size %d, file holds %d", size, len(b)-8)
+	}
+
+	// Walk the chunks, counting the frames of the movi list and the entries
+	// of the index, which also starts with 00dc
+	var totalFrames, streamFrames, index int
+	var movi []int // offsets of the frames from the movi fourcc
+	moviStart := -1
+	var walk func(data []byte, off int, list string)
+	walk = func(data []byte, off int, list string) {
+		for len(data) >= 8 {
+			id, size := string(data[:4]), int(binary.LittleEndian.Uint32(data[4:]))
+			if 8+size > len(data) {
+				t.Fatalf("chunk %s of %d bytes overflows its list", id, size)
+			}
+			body := data[8 : 8+size]
+			switch {
+			case id == "LIST":
+				if string(body[:4]) == "movi" {
+					moviStart = off + 8
+				}
+				walk(body[4:], off+12, string(body[:4]))
+			case id == "avih":
+				totalFrames = int(binary.LittleEndian.Uint32(body[16:]))
+			case id == "strh":
+				streamFrames = int(binary.LittleEndian.Uint32(body[32:]))
+			case id == "idx1":
+				index = size / 16
+				for i := 0; i < index; i++ {
+					e := body[16*i:]
+					if string(e[:4]) != "00dc" || i >= len(movi) || int(binary.LittleEndian.Uint32(e[8:])) != movi[i] {
+						t.Errorf("index entry %d: %q at %d", i, e[:4], binary.LittleEndian.Uint32(e[8:]))
+					}
+				}
+			case id == "00dc" && list == "movi":
+				movi = append(movi, off-moviStart)
+				if _, err := jpeg.Decode(bytes.NewReader(body)); err != nil {
+					t.Errorf("frame %d: %v", len(movi), err)
+				}
+			}
+			off += 8 + size + size%2
+			data = data[8+size+size%2:]
+		}
+	}
+	walk(b[12:], 12, "")
+	if len(movi) != frames || totalFrames != frames || streamFrames != frames || index != frames {
+		t.Errorf("%d frames written: %d in movi, %d in avih, %d in strh, %d in idx1", frames, len(movi), totalFrames, streamFrames, index)
+	}
+}
+
+// seekBuffer is an in-memory io.WriteSeeker.
+type seekBuffer struct {
+	buf []byte
+	pos int
+}
+
+func (s *seekBuffer) Write(p []byte) (int, error) {
+	if need := s.pos + len(p); need > len(s.buf) {
+		s.buf = append(s.buf, make([]byte, need-len(s.buf))...)
+	}
+	s.pos += copy(s.buf[s.pos:], p)
+	return len(p), nil
+}
+
+func (s *seekBuffer) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += int64(s.pos)
+	case io.SeekEnd:
+		offset += int64(len(s.buf))
+	}
+	s.pos = int(offset)
+	return offset, nil
+}